@@ -25,7 +25,27 @@ func GenerateAccountName(name string) string {
 }
 
 // GenerateAccount - wrapper around sdkAccounts.GenerateAccount
-func GenerateAccount(name string) (sdkAccounts.Account, error) {
+// Accounts are derived from account.mnemonic if it's set and only live in memory if account.ephemeral is enabled
+func GenerateAccount(name string) (account sdkAccounts.Account, err error) {
+	if config.Configuration.Account.Mnemonic != "" || config.Configuration.Account.Ephemeral {
+		if config.Configuration.Account.Mnemonic != "" {
+			account, err = GenerateHDAccount(name)
+		} else {
+			account, err = GenerateEphemeralAccount(name)
+		}
+
+		if err == nil {
+			Register(account)
+		}
+
+		return account, err
+	}
+
+	return PerformGenerateAccount(name, 3)
+}
+
+// GenerateKeystoreAccount - generates an account that is always stored in the keystore, regardless of account.ephemeral
+func GenerateKeystoreAccount(name string) (sdkAccounts.Account, error) {
 	return PerformGenerateAccount(name, 3)
 }
 
@@ -43,6 +63,10 @@ func PerformGenerateAccount(name string, attempts int) (sdkAccounts.Account, err
 		}
 	}
 
+	if err == nil {
+		Register(account)
+	}
+
 	return account, err
 }
 
@@ -0,0 +1,144 @@
+package accounts
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
+	sdkAccounts "github.com/harmony-one/go-lib/accounts"
+	goSdkAccount "github.com/harmony-one/go-sdk/pkg/account"
+	goSdkAddress "github.com/harmony-one/go-sdk/pkg/address"
+	"github.com/harmony-one/harmony-tf/config"
+	hmyAccounts "github.com/harmony-one/harmony/accounts"
+	hmyKeystore "github.com/harmony-one/harmony/accounts/keystore"
+)
+
+var ephemeral = struct {
+	sync.Mutex
+	dir      string
+	keystore *hmyKeystore.KeyStore
+	keys     map[string]*ecdsa.PrivateKey
+	imported map[string]hmyAccounts.Account
+}{
+	keys:     make(map[string]*ecdsa.PrivateKey),
+	imported: make(map[string]hmyAccounts.Account),
+}
+
+// GenerateEphemeralAccount - generates an account whose private key only lives in memory
+// The go-lib signing functions require an unlocked keystore - the key is therefore briefly written to a private temporary keystore, unlocked and then immediately deleted from disk again
+func GenerateEphemeralAccount(name string) (sdkAccounts.Account, error) {
+	privateKey, err := ethCrypto.GenerateKey()
+	if err != nil {
+		return sdkAccounts.Account{}, err
+	}
+
+	return generateEphemeralAccountFromKey(name, privateKey)
+}
+
+func generateEphemeralAccountFromKey(name string, privateKey *ecdsa.PrivateKey) (sdkAccounts.Account, error) {
+	ephemeral.Lock()
+	defer ephemeral.Unlock()
+
+	if ephemeral.keystore == nil {
+		dir, err := ioutil.TempDir(ephemeralKeystoreBaseDir(), "harmony-tf-keys-")
+		if err != nil {
+			return sdkAccounts.Account{}, err
+		}
+		ephemeral.dir = dir
+		ephemeral.keystore = hmyKeystore.NewKeyStore(dir, hmyKeystore.LightScryptN, hmyKeystore.LightScryptP)
+	}
+
+	address := goSdkAddress.ToBech32(ethCrypto.PubkeyToAddress(privateKey.PublicKey))
+	if keystoreAccount, ok := ephemeral.imported[address]; ok {
+		// Derived accounts can be requested multiple times - the key is already unlocked in the ephemeral keystore
+		ephemeral.keys[address] = privateKey
+		return ephemeralAccount(name, address, &keystoreAccount), nil
+	}
+
+	// The keystore recreates its directory for every import - remove it again so that no key material directory outlives the import
+	defer os.RemoveAll(ephemeral.dir)
+
+	keystoreAccount, err := ephemeral.keystore.ImportECDSA(privateKey, config.Configuration.Account.Passphrase)
+	if err != nil {
+		return sdkAccounts.Account{}, err
+	}
+
+	err = ephemeral.keystore.Unlock(keystoreAccount, config.Configuration.Account.Passphrase)
+	if err != nil {
+		return sdkAccounts.Account{}, err
+	}
+
+	ephemeral.keys[address] = privateKey
+	ephemeral.imported[address] = keystoreAccount
+
+	return ephemeralAccount(name, address, &keystoreAccount), nil
+}
+
+func ephemeralAccount(name string, address string, keystoreAccount *hmyAccounts.Account) sdkAccounts.Account {
+	return sdkAccounts.Account{
+		Name:       name,
+		Address:    address,
+		Passphrase: config.Configuration.Account.Passphrase,
+		Keystore:   ephemeral.keystore,
+		Account:    keystoreAccount,
+		Unlocked:   true,
+	}
+}
+
+// IsEphemeral - whether or not a given account only lives in memory
+func IsEphemeral(account *sdkAccounts.Account) bool {
+	ephemeral.Lock()
+	defer ephemeral.Unlock()
+
+	_, ok := ephemeral.keys[account.Address]
+
+	return ok
+}
+
+// Persist - imports an ephemeral account into the keystore so that it survives the current run, e.g. when it still holds funds after a failed teardown
+// Accounts that already live in the keystore are left untouched
+func Persist(account *sdkAccounts.Account) error {
+	ephemeral.Lock()
+	privateKey, ok := ephemeral.keys[account.Address]
+	ephemeral.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	if _, err := ImportPrivateKeyAccount(hex.EncodeToString(ethCrypto.FromECDSA(privateKey)), account.Name, account.Address); err != nil {
+		return err
+	}
+
+	ephemeral.Lock()
+	delete(ephemeral.keys, account.Address)
+	ephemeral.Unlock()
+
+	return nil
+}
+
+// RemoveAccount - removes a generated account from the keystore (or forgets it if it only lives in memory) and unregisters it
+func RemoveAccount(account *sdkAccounts.Account) {
+	ephemeral.Lock()
+	_, ok := ephemeral.keys[account.Address]
+	delete(ephemeral.keys, account.Address)
+	ephemeral.Unlock()
+
+	if !ok {
+		goSdkAccount.RemoveAccount(account.Name)
+	}
+
+	Unregister(account.Name)
+}
+
+func ephemeralKeystoreBaseDir() string {
+	// Prefer a memory backed file system so that the keys never touch a physical disk
+	if info, err := os.Stat("/dev/shm"); err == nil && info.IsDir() {
+		return "/dev/shm"
+	}
+
+	return ""
+}
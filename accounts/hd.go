@@ -0,0 +1,188 @@
+package accounts
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
+	sdkAccounts "github.com/harmony-one/go-lib/accounts"
+	goSdkAddress "github.com/harmony-one/go-sdk/pkg/address"
+	goSdkKeys "github.com/harmony-one/go-sdk/pkg/keys"
+	"github.com/harmony-one/go-sdk/pkg/store"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/utils"
+	"gopkg.in/yaml.v2"
+)
+
+var hdMutex sync.Mutex
+
+// HDAllocations - represents the BIP44 indexes allocated to generated account names on a given network
+type HDAllocations struct {
+	Network string            `yaml:"network"`
+	Indexes map[string]uint32 `yaml:"indexes"`
+}
+
+// HDAccount - represents an allocated HD account
+type HDAccount struct {
+	Name  string
+	Index uint32
+}
+
+// HDAllocationsPath - the path to the HD index allocations for the current network
+func HDAllocationsPath() string {
+	return filepath.Join(config.Configuration.StatePath(), "hd_accounts.yml")
+}
+
+// LoadHDAllocations - loads the HD index allocations for the current network
+func LoadHDAllocations() (allocations HDAllocations, err error) {
+	if err = utils.ParseYaml(HDAllocationsPath(), &allocations); err != nil {
+		return HDAllocations{}, err
+	}
+
+	if allocations.Network == "" {
+		allocations.Network = config.Configuration.Network.Name
+	}
+
+	if allocations.Indexes == nil {
+		allocations.Indexes = make(map[string]uint32)
+	}
+
+	return allocations, nil
+}
+
+// Save - persists the HD index allocations to disk
+func (allocations *HDAllocations) Save() error {
+	path := HDAllocationsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(allocations)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// AllocateHDIndex - returns the BIP44 index of a given account name - the index is derived from the name itself (a sha256 hash truncated to 31 bits)
+// Account names are derived from the test case name and the role of the account, so a test case always uses the same addresses on a given network, on any machine
+// Allocations are recorded so that recover can find the accounts again - losing the record doesn't change any index
+func AllocateHDIndex(name string) (uint32, error) {
+	hdMutex.Lock()
+	defer hdMutex.Unlock()
+
+	allocations, err := LoadHDAllocations()
+	if err != nil {
+		return 0, err
+	}
+
+	index := HDIndex(name)
+	for allocatedName, allocated := range allocations.Indexes {
+		if allocated == index && allocatedName != name {
+			return 0, fmt.Errorf("the account names %s and %s both derive the HD index %d - please rename one of the test cases", allocatedName, name, index)
+		}
+	}
+
+	if allocated, ok := allocations.Indexes[name]; ok && allocated == index {
+		return index, nil
+	}
+
+	allocations.Indexes[name] = index
+	if err := allocations.Save(); err != nil {
+		return 0, err
+	}
+
+	return index, nil
+}
+
+// HDIndex - derives the BIP44 index of a given account name
+func HDIndex(name string) uint32 {
+	hash := sha256.Sum256([]byte(name))
+	return binary.BigEndian.Uint32(hash[:4]) & 0x7fffffff
+}
+
+// AllocatedHDAccounts - lists all HD accounts allocated on the current network, ordered by index
+func AllocatedHDAccounts() (accs []HDAccount, err error) {
+	hdMutex.Lock()
+	defer hdMutex.Unlock()
+
+	allocations, err := LoadHDAllocations()
+	if err != nil {
+		return nil, err
+	}
+
+	for name, index := range allocations.Indexes {
+		accs = append(accs, HDAccount{Name: name, Index: index})
+	}
+
+	sort.Slice(accs, func(i, j int) bool {
+		return accs[i].Index < accs[j].Index
+	})
+
+	return accs, nil
+}
+
+// DeriveHDPrivateKey - derives the private key for a given index along the BIP44 path m/44'/1023'/0'/0/index using the configured mnemonic
+func DeriveHDPrivateKey(index uint32) *ecdsa.PrivateKey {
+	privateKey, _ := goSdkKeys.FromMnemonicSeedAndPassphrase(config.Configuration.Account.Mnemonic, int(index))
+	return privateKey.ToECDSA()
+}
+
+// GenerateHDAccount - derives the account allocated to a given name from the configured mnemonic
+// The account only lives in memory if account.ephemeral is enabled - otherwise it's imported into the keystore
+func GenerateHDAccount(name string) (sdkAccounts.Account, error) {
+	index, err := AllocateHDIndex(name)
+	if err != nil {
+		return sdkAccounts.Account{}, err
+	}
+
+	if config.Configuration.Account.Ephemeral {
+		return RecoverHDAccount(name, index)
+	}
+
+	privateKey := DeriveHDPrivateKey(index)
+	address := goSdkAddress.ToBech32(ethCrypto.PubkeyToAddress(privateKey.PublicKey))
+
+	// A previous run might have left a (randomly generated) account with the same name behind - it might still hold funds, so it's renamed rather than removed
+	if existing := sdkAccounts.FindAccountByName(name); existing.Address != "" && existing.Address != address {
+		renamed, err := renameKeystoreAccount(name)
+		if err != nil {
+			return sdkAccounts.Account{}, err
+		}
+		logger.WarningLog(fmt.Sprintf("Renamed the keystore account %s, address: %s to %s since it doesn't match the derived address %s - use sweep to return its funds", name, existing.Address, renamed, address), true)
+	}
+
+	account, err := ImportPrivateKeyAccount(hex.EncodeToString(ethCrypto.FromECDSA(privateKey)), name, address)
+	if err != nil {
+		return account, err
+	}
+	account.Passphrase = config.Configuration.Account.Passphrase
+
+	return account, nil
+}
+
+// RecoverHDAccount - re-derives an in-memory HD account for a given name and index without touching the keystore
+func RecoverHDAccount(name string, index uint32) (sdkAccounts.Account, error) {
+	return generateEphemeralAccountFromKey(name, DeriveHDPrivateKey(index))
+}
+
+// renameKeystoreAccount - moves a keystore account to an unused name that keeps its test account prefix, i.e. it's still picked up by sweep
+func renameKeystoreAccount(name string) (string, error) {
+	renamed := fmt.Sprintf("%s_Orphaned_%d", name, time.Now().UTC().UnixNano())
+	if err := os.Rename(filepath.Join(store.DefaultLocation(), name), filepath.Join(store.DefaultLocation(), renamed)); err != nil {
+		return "", err
+	}
+
+	return renamed, nil
+}
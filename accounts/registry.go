@@ -0,0 +1,61 @@
+package accounts
+
+import (
+	"sync"
+
+	sdkAccounts "github.com/harmony-one/go-lib/accounts"
+)
+
+var registry = struct {
+	sync.Mutex
+	accounts  map[string]sdkAccounts.Account
+	generated map[string]sdkAccounts.Account
+}{
+	accounts:  make(map[string]sdkAccounts.Account),
+	generated: make(map[string]sdkAccounts.Account),
+}
+
+// Register - registers a generated account as live, i.e. it might still hold funds that need to be returned
+func Register(account sdkAccounts.Account) {
+	if account.Name == "" || account.Address == "" {
+		return
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.accounts[account.Name] = account
+	registry.generated[account.Name] = account
+}
+
+// Unregister - removes a previously registered account from the registry, e.g. after it's been torn down
+func Unregister(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	delete(registry.accounts, name)
+}
+
+// LiveAccounts - returns all generated accounts that haven't been torn down yet
+func LiveAccounts() (accs []sdkAccounts.Account) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, account := range registry.accounts {
+		accs = append(accs, account)
+	}
+
+	return accs
+}
+
+// GeneratedAccounts - returns all accounts that have been generated during the current run, including the ones that have been torn down
+func GeneratedAccounts() (accs []sdkAccounts.Account) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	for _, account := range registry.generated {
+		accs = append(accs, account)
+	}
+
+	return accs
+}
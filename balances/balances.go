@@ -2,10 +2,14 @@ package balances
 
 import (
 	"fmt"
+	"math/big"
 	"time"
 
 	sdkAccounts "github.com/harmony-one/go-lib/accounts"
 	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/metrics"
+	"github.com/harmony-one/harmony-tf/waits"
+	"github.com/harmony-one/harmony/common/denominations"
 	"github.com/harmony-one/harmony/numeric"
 )
 
@@ -14,6 +18,15 @@ func GetShardBalance(address string, shardID uint32) (numeric.Dec, error) {
 	return config.Configuration.Network.API.GetShardBalance(address, shardID)
 }
 
+// FromAtto - converts an atto denominated amount (e.g. a delegation amount returned by the RPC API) to a decimal ONE amount
+func FromAtto(amount *big.Int) numeric.Dec {
+	if amount == nil {
+		return numeric.NewDec(0)
+	}
+
+	return numeric.NewDecFromBigInt(amount).Quo(numeric.NewDec(denominations.One))
+}
+
 // GetNonZeroShardBalance - gets the balance for a given address and shard with auto retry upon failure/balance being nil/balance being zero
 func GetNonZeroShardBalance(address string, shardID uint32) (balance numeric.Dec, err error) {
 	attempts := config.Configuration.Network.Balances.Retry.Attempts
@@ -30,7 +43,7 @@ func GetNonZeroShardBalance(address string, shardID uint32) (balance numeric.Dec
 			break
 		}
 
-		time.Sleep(time.Duration(config.Configuration.Network.Balances.Retry.Wait) * time.Second)
+		waitForNextBlock(shardID)
 	}
 
 	return balance, err
@@ -52,7 +65,21 @@ func GetExpectedShardBalance(address string, shardID uint32, expectedBalance num
 			return numeric.NewDec(0), fmt.Errorf("failed to retrieve expected balance %f for address %s in shard %d", expectedBalance, address, shardID)
 		}
 
-		time.Sleep(time.Duration(config.Configuration.Network.Balances.Retry.Wait) * time.Second)
+		waitForNextBlock(shardID)
+	}
+}
+
+// waitForNextBlock - waits for the next block of a given shard before a balance is queried again, at most network.balances.retry.wait seconds
+func waitForNextBlock(shardID uint32) {
+	wait := time.Duration(config.Configuration.Network.Balances.Retry.Wait) * time.Second
+	if wait <= 0 {
+		return
+	}
+
+	startedAt := time.Now()
+	if _, err := waits.Blocks(shardID, 1, wait); err != nil {
+		// The block number couldn't be retrieved - wait out the rest of the retry wait time
+		metrics.Sleep("balance retry wait", wait-time.Since(startedAt))
 	}
 }
 
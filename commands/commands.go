@@ -0,0 +1,15 @@
+package commands
+
+import (
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/network"
+)
+
+// configure - configures the framework for commands running outside of the regular test suite
+func configure() error {
+	if err := config.Configure(config.Args.Path); err != nil {
+		return err
+	}
+
+	return network.Setup()
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/keys"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var format string
+
+	keysCommand := &cobra.Command{
+		Use:   "keys",
+		Short: "Manage the keys in keys/<network>/",
+	}
+
+	statusCommand := &cobra.Command{
+		Use:   "status",
+		Short: "Show the per shard balances of all keys in keys/<network>/ and the funding account",
+		Long:  "Identifies all private keys and keystore files in keys/<network>/ and shows their balances in every shard, the balances of the funding account and the total usable funds - nothing is imported into or removed from the keystore",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := keysStatus(format); err != nil {
+				return err
+			}
+			os.Exit(0)
+			return nil
+		},
+	}
+	statusCommand.Flags().StringVar(&format, "format", "table", "--format <table|json>")
+
+	restoreCommand := &cobra.Command{
+		Use:   "restore [address...]",
+		Short: "Restore quarantined keystore files",
+		Long:  "Moves quarantined keystore files from keys/<network>/.quarantine/ back to keys/<network>/ and resets their low balance observations - restores all quarantined keys if no addresses are specified",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := keysRestore(args); err != nil {
+				return err
+			}
+			os.Exit(0)
+			return nil
+		},
+	}
+
+	keysCommand.AddCommand(statusCommand)
+	keysCommand.AddCommand(restoreCommand)
+	config.RootCommand.AddCommand(keysCommand)
+}
+
+func keysStatus(format string) error {
+	format = strings.ToLower(format)
+	if format != "table" && format != "json" {
+		return fmt.Errorf("invalid format %s - valid options: table, json", format)
+	}
+
+	if err := configure(); err != nil {
+		return err
+	}
+
+	fundingAddress := ""
+	if err := funding.LookupFundingAccount(); err != nil {
+		if format == "table" {
+			logger.WarningLog(err.Error(), true)
+		}
+	} else {
+		fundingAddress = config.Configuration.Funding.Account.Address
+	}
+
+	inventory, err := keys.Status(fundingAddress)
+	if err != nil {
+		return err
+	}
+
+	if format == "json" {
+		data, err := inventory.JSON()
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Print(inventory.Table())
+
+	return nil
+}
+
+func keysRestore(addresses []string) error {
+	if err := configure(); err != nil {
+		return err
+	}
+
+	quarantined, err := keys.QuarantinedKeys()
+	if err != nil {
+		return err
+	}
+
+	if len(addresses) == 0 {
+		for address := range quarantined {
+			addresses = append(addresses, address)
+		}
+	}
+
+	if len(addresses) == 0 {
+		fmt.Println(fmt.Sprintf("There are no quarantined keys in %s", keys.QuarantinePath()))
+		return nil
+	}
+
+	for _, address := range addresses {
+		path, ok := quarantined[address]
+		if !ok {
+			logger.WarningLog(fmt.Sprintf("Couldn't find a quarantined key for the address %s", address), true)
+			continue
+		}
+
+		restoredPath, err := keys.Restore(address, path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(fmt.Sprintf("Restored key %s to %s", address, restoredPath))
+	}
+
+	return nil
+}
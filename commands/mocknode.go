@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	sdkNetworkUtils "github.com/harmony-one/go-lib/network/utils"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/mocknode"
+	"github.com/harmony-one/harmony/numeric"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	options := mocknode.Options{}
+	var blockTime time.Duration
+	var chainID int64
+	var fund []string
+
+	mockNodeCommand := &cobra.Command{
+		Use:   "mock-node",
+		Short: "Run an in-process mock Harmony node for offline end-to-end runs",
+		Long:  "Simulates a sharded Harmony chain in memory - balances, nonces, signed (cross shard) transfers, receipts and create validator/edit validator/delegate/undelegate/collect rewards staking transactions - and serves the JSON-RPC endpoints the test suite uses, newHeads/newPendingTransactions WebSocket subscriptions as well as a faucet endpoint. Point --nodes at the printed urls to run the test suite without a live network",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.BlockTime = blockTime
+			if chainID > 0 {
+				options.ChainID = big.NewInt(chainID)
+			}
+			if err := runMockNode(options, fund); err != nil {
+				return err
+			}
+			os.Exit(0)
+			return nil
+		},
+	}
+	mockNodeCommand.Flags().IntVar(&options.Shards, "shards", 2, "--shards <count>")
+	mockNodeCommand.Flags().StringVar(&options.Host, "host", "127.0.0.1", "--host <host>")
+	mockNodeCommand.Flags().IntVar(&options.Port, "port", 9500, "--port <port> - shard n listens on port+n")
+	mockNodeCommand.Flags().DurationVar(&blockTime, "block-time", time.Second, "--block-time <duration>")
+	mockNodeCommand.Flags().Uint64Var(&options.BlocksPerEpoch, "blocks-per-epoch", 30, "--blocks-per-epoch <count>")
+	mockNodeCommand.Flags().Int64Var(&chainID, "chain-id", 0, "--chain-id <chain id> - defaults to the chain id of --network (required for networks defined in the networks section of config.yml)")
+	mockNodeCommand.Flags().StringSliceVar(&fund, "fund", []string{}, "--fund <address>=<amount>,<address>=<amount> - funds the addresses with the given amount (in ONE) in every shard")
+
+	config.RootCommand.AddCommand(mockNodeCommand)
+}
+
+func runMockNode(options mocknode.Options, fund []string) error {
+	if options.ChainID == nil {
+		chainID, err := sdkNetworkUtils.IdentifyNetworkChainID(config.Args.Network)
+		if err != nil {
+			return err
+		}
+		options.ChainID = chainID.Value
+	}
+
+	options.Genesis = make(map[string]numeric.Dec)
+	for _, entry := range fund {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid --fund entry %s - expected <address>=<amount>", entry)
+		}
+
+		amount, err := numeric.NewDecFromStr(strings.TrimSpace(parts[1]))
+		if err != nil {
+			return fmt.Errorf("invalid amount for --fund entry %s: %s", entry, err.Error())
+		}
+		options.Genesis[strings.TrimSpace(parts[0])] = amount
+	}
+
+	chain, err := mocknode.New(options)
+	if err != nil {
+		return err
+	}
+
+	if err := chain.Start(); err != nil {
+		return err
+	}
+	defer chain.Stop()
+
+	logger.Log(fmt.Sprintf("Mock node for network %s (chain id %s) is running with %d shard(s):", config.Args.Network, options.ChainID.String(), len(chain.URLs)), true)
+	for shardID, url := range chain.URLs {
+		logger.Log(fmt.Sprintf("  Shard %d: %s (faucet: %s/faucet, websocket: %s)", shardID, url, url, chain.WebSocketURLs[shardID]), true)
+	}
+	logger.Log(fmt.Sprintf("Run the test suite against it using: --network %s --mode custom --nodes %s", config.Args.Network, strings.Join(chain.URLs, ",")), true)
+	logger.Log("Add --websocket to confirm transactions using the mock node's WebSocket subscriptions instead of polling receipts", true)
+	logger.Log(fmt.Sprintf("Fund the test accounts by using the faucet funding source (funding.sources: [faucet]) with funding.faucet.url set to %s/faucet", chain.URLs[0]), true)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	<-signals
+
+	logger.Log("Stopping the mock node", true)
+
+	return nil
+}
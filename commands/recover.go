@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	sdkAccounts "github.com/harmony-one/go-lib/accounts"
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/staking"
+	"github.com/harmony-one/harmony-tf/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var dryRun bool
+
+	recoverCommand := &cobra.Command{
+		Use:   "recover",
+		Short: "Re-derive all test accounts allocated from the configured mnemonic and return their funds to the funding account",
+		Long:  "Re-derives every test account that has been allocated a BIP44 index from account.mnemonic on the configured network and returns their funds (except gas) to the funding account - no keystore files are required. Validators in the validator pool are never recovered - use validators prune for those",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := recoverAccounts(dryRun); err != nil {
+				return err
+			}
+			os.Exit(0)
+			return nil
+		},
+	}
+	recoverCommand.Flags().BoolVar(&dryRun, "dry-run", false, "--dry-run")
+
+	config.RootCommand.AddCommand(recoverCommand)
+}
+
+func recoverAccounts(dryRun bool) error {
+	if err := configure(); err != nil {
+		return err
+	}
+
+	if config.Configuration.Account.Mnemonic == "" {
+		return errors.New("account.mnemonic has to be set in order to recover derived test accounts")
+	}
+
+	pool, err := staking.LoadValidatorPool()
+	if err != nil {
+		return err
+	}
+
+	pooledAddresses := []string{}
+	for _, pooled := range pool.Validators {
+		pooledAddresses = append(pooledAddresses, pooled.Address)
+	}
+
+	allocated, err := accounts.AllocatedHDAccounts()
+	if err != nil {
+		return err
+	}
+
+	derived := []sdkAccounts.Account{}
+	for _, hdAccount := range allocated {
+		account, err := accounts.RecoverHDAccount(hdAccount.Name, hdAccount.Index)
+		if err != nil {
+			return err
+		}
+
+		if !utils.StringSliceContains(pooledAddresses, account.Address) {
+			derived = append(derived, account)
+		}
+	}
+
+	summary, err := funding.SweepAccounts(derived, dryRun)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(summary.String())
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/staking"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var dryRun bool
+
+	sweepCommand := &cobra.Command{
+		Use:   "sweep",
+		Short: "Return the funds held by orphaned test accounts to the funding account",
+		Long:  "Finds all accounts generated by test cases for the configured network, returns their funds (except gas) to the funding account and removes them from the keystore once all of their balances are zero. Validators in the validator pool are never swept - use validators prune for those",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := sweep(dryRun); err != nil {
+				return err
+			}
+			os.Exit(0)
+			return nil
+		},
+	}
+	sweepCommand.Flags().BoolVar(&dryRun, "dry-run", false, "--dry-run")
+
+	config.RootCommand.AddCommand(sweepCommand)
+}
+
+func sweep(dryRun bool) error {
+	if err := configure(); err != nil {
+		return err
+	}
+
+	pool, err := staking.LoadValidatorPool()
+	if err != nil {
+		return err
+	}
+
+	excludedNames := []string{}
+	for _, pooled := range pool.Validators {
+		excludedNames = append(excludedNames, pooled.AccountName)
+	}
+
+	summary, err := funding.Sweep(excludedNames, dryRun)
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(summary.String())
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/staking"
+	"github.com/harmony-one/harmony-tf/utils"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	validatorsCommand := &cobra.Command{
+		Use:   "validators",
+		Short: "Manage the validators in the validator pool",
+	}
+
+	validatorsCommand.AddCommand(&cobra.Command{
+		Use:   "prune [address...]",
+		Short: "Undelegate and reclaim the stake of pooled validators (all pooled validators unless specific addresses are supplied)",
+		Long:  "Undelegates the self delegation of pooled validators. Once the undelegated stake has been unlocked, running prune again returns the funds to the funding account and removes the validators from the pool",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := pruneValidators(args); err != nil {
+				return err
+			}
+			os.Exit(0)
+			return nil
+		},
+	})
+
+	config.RootCommand.AddCommand(validatorsCommand)
+}
+
+func pruneValidators(addresses []string) error {
+	if err := configure(); err != nil {
+		return err
+	}
+
+	pool, err := staking.LoadValidatorPool()
+	if err != nil {
+		return err
+	}
+
+	if len(pool.Validators) == 0 {
+		fmt.Printf("There are no validators in the validator pool %s\n", staking.ValidatorPoolPath())
+		return nil
+	}
+
+	removed := []string{}
+	for i := range pool.Validators {
+		pooled := &pool.Validators[i]
+		if len(addresses) > 0 && !utils.StringSliceContains(addresses, pooled.Address) {
+			continue
+		}
+
+		pooledRemoved, err := pooled.Prune(true)
+		if err != nil {
+			fmt.Printf("Failed to prune the pooled validator %s - error: %s\n", pooled.Address, err.Error())
+			continue
+		}
+
+		if pooledRemoved {
+			removed = append(removed, pooled.Address)
+		}
+	}
+
+	for _, address := range removed {
+		pool.Remove(address)
+	}
+
+	if err := pool.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Pruned a total of %d validator(s) - %d validator(s) remain in the validator pool\n", len(removed), len(pool.Validators))
+
+	return nil
+}
@@ -31,6 +31,8 @@ type CommandArguments struct {
 	Verbose        bool
 	VerboseGoSDK   bool
 	PprofPort      int
+	Cassettes      string
+	WebSocket      bool
 }
 
 var (
@@ -67,6 +69,8 @@ func init() {
 	RootCommand.PersistentFlags().BoolVar(&Args.Verbose, "verbose", false, "--verbose")
 	RootCommand.PersistentFlags().BoolVar(&Args.VerboseGoSDK, "verbose-go-sdk", false, "--verbose-go-sdk")
 	RootCommand.PersistentFlags().IntVar(&Args.PprofPort, "pprof-port", -1, "--pprof-port <port>")
+	RootCommand.PersistentFlags().StringVar(&Args.Cassettes, "cassettes", "", "--cassettes <record|replay>")
+	RootCommand.PersistentFlags().BoolVar(&Args.WebSocket, "websocket", false, "--websocket")
 
 	RootCommand.AddCommand(&cobra.Command{
 		Use:   "version",
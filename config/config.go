@@ -1,6 +1,13 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/gookit/color"
@@ -8,17 +15,20 @@ import (
 	sdkNetworkTypes "github.com/harmony-one/go-lib/network/types/network"
 	sdkValidator "github.com/harmony-one/go-lib/staking/validator"
 	"github.com/harmony-one/go-sdk/pkg/common"
+	goSdkSharding "github.com/harmony-one/go-sdk/pkg/sharding"
 	"github.com/harmony-one/harmony/numeric"
 	"github.com/pkg/errors"
+	"github.com/tyler-smith/go-bip39"
 )
 
 // Config - represents the general configuration
 type Config struct {
-	Framework  Framework `yaml:"framework"`
-	Network    Network   `yaml:"network"`
-	Account    Account   `yaml:"account"`
-	Funding    Funding   `yaml:"funding"`
-	Export     Export    `yaml:"export"`
+	Framework  Framework                     `yaml:"framework"`
+	Network    Network                       `yaml:"network"`
+	Account    Account                       `yaml:"account"`
+	Funding    Funding                       `yaml:"funding"`
+	Export     Export                        `yaml:"export"`
+	Networks   map[string]*NetworkDefinition `yaml:"networks"`
 	Configured bool
 }
 
@@ -34,6 +44,8 @@ type Framework struct {
 	StartTime             time.Time               `yaml:"-"`
 	EndTime               time.Time               `yaml:"-"`
 	CurrentValidator      *sdkValidator.Validator `yaml:"-"`
+	ValidatorPool         bool                    `yaml:"validator_pool"`
+	Snapshots             Snapshots               `yaml:"snapshots"`
 	Styling               Styling                 `yaml:"-"`
 }
 
@@ -56,26 +68,36 @@ type Styling struct {
 
 // Network - represents the network settings group
 type Network struct {
-	Name                 string                  `yaml:"name"`
-	Mode                 string                  `yaml:"mode"`
-	Node                 string                  `yaml:"-"`
-	Nodes                []string                `yaml:"-"`
-	Endpoints            map[string][]string     `yaml:"endpoints"`
-	Shards               int                     `yaml:"-"`
-	Timeout              int                     `yaml:"timeout"`
-	CrossShardTxWaitTime uint32                  `yaml:"cross_shard_tx_wait_time"`
-	StakingWaitTime      uint32                  `yaml:"staking_wait_time"`
-	Gas                  sdkNetworkTypes.Gas     `yaml:"gas"`
-	API                  sdkNetworkTypes.Network `yaml:"-"`
-	Retry                Retry                   `yaml:"retry"`
-	Balances             Balances                `yaml:"balances"`
+	Name              string                  `yaml:"name"`
+	Mode              string                  `yaml:"mode"`
+	Node              string                  `yaml:"-"`
+	Nodes             []string                `yaml:"-"`
+	Endpoints         map[string][]string     `yaml:"endpoints"`
+	Shards            int                     `yaml:"-"`
+	Timeout           int                     `yaml:"timeout"`
+	StakingWaitTime   uint32                  `yaml:"staking_wait_time"`
+	Gas               sdkNetworkTypes.Gas     `yaml:"gas"`
+	API               sdkNetworkTypes.Network `yaml:"-"`
+	StakingChainID    *common.ChainID         `yaml:"-"`
+	TimeoutMultiplier float64                 `yaml:"-"`
+	Retry             Retry                   `yaml:"retry"`
+	Balances          Balances                `yaml:"balances"`
+	Health            Health                  `yaml:"health"`
+	Cassettes         Cassettes               `yaml:"cassettes"`
+	RateLimit         RateLimit               `yaml:"rate_limit"`
+	Waits             Waits                   `yaml:"waits"`
+	WebSocket         WebSocket               `yaml:"websocket"`
 }
 
 // Account - represents the account settings group
 type Account struct {
 	Passphrase       string `yaml:"passphrase"`
 	RemoveEmpty      bool   `yaml:"remove_empty"`
+	QuarantineAfter  int    `yaml:"quarantine_after"`
 	UseAllInKeystore bool   `yaml:"use_all_in_keystore"`
+	Ephemeral        bool   `yaml:"ephemeral"`
+	RawMnemonic      string `yaml:"mnemonic"`
+	Mnemonic         string `yaml:"-"`
 }
 
 // Funding - represents the funding settings group
@@ -83,11 +105,48 @@ type Funding struct {
 	Account         sdkAccounts.Account `yaml:"account"`
 	RawMinimumFunds string              `yaml:"minimum_funds"`
 	MinimumFunds    numeric.Dec         `yaml:"-"`
+	RawBudget       string              `yaml:"budget"`
+	Budget          numeric.Dec         `yaml:"-"`
 	Timeout         int                 `yaml:"timeout"`
 	Retry           Retry               `yaml:"retry"`
 	Verbose         bool                `yaml:"verbose"`
 	Shards          string              `yaml:"shards"`
 	Gas             sdkNetworkTypes.Gas `yaml:"gas"`
+	FanOut          FanOut              `yaml:"fan_out"`
+	RawTarget       string              `yaml:"target"`
+	Target          numeric.Dec         `yaml:"-"`
+	Sources         []string            `yaml:"sources"`
+	Faucet          Faucet              `yaml:"faucet"`
+	Manual          Manual              `yaml:"manual"`
+	Conservation    Conservation        `yaml:"conservation"`
+}
+
+// Faucet - settings for requesting funds from an HTTP faucet
+type Faucet struct {
+	URL         string            `yaml:"url"`
+	Method      string            `yaml:"method"`
+	Body        string            `yaml:"body"`
+	Headers     map[string]string `yaml:"headers"`
+	Interval    int               `yaml:"interval"`
+	MaxRequests int               `yaml:"max_requests"`
+}
+
+// Manual - settings for waiting on external top ups of the funding account
+type Manual struct {
+	Timeout int `yaml:"timeout"`
+}
+
+// Conservation - settings for checking that the framework doesn't lose funds over the course of a test suite run
+type Conservation struct {
+	Enabled      bool        `yaml:"enabled"`
+	RawThreshold string      `yaml:"threshold"`
+	Threshold    numeric.Dec `yaml:"-"`
+}
+
+// FanOut - settings for funding large numbers of accounts using intermediate sub-funders
+type FanOut struct {
+	SubFunders int   `yaml:"sub_funders"`
+	Threshold  int64 `yaml:"threshold"`
 }
 
 // Retry - settings for RPC retries
@@ -101,6 +160,59 @@ type Balances struct {
 	Retry Retry `yaml:"retry"`
 }
 
+// Health - settings for endpoint health checks and failover
+type Health struct {
+	Enabled     bool   `yaml:"enabled"`
+	Interval    int    `yaml:"interval"`
+	MaxBlockLag uint64 `yaml:"max_block_lag"`
+}
+
+// Cassettes - settings for recording and replaying JSON-RPC traffic
+type Cassettes struct {
+	Mode string `yaml:"mode"`
+	Path string `yaml:"path"`
+}
+
+// RateLimit - settings for client side rate limiting and backoff of RPC calls
+type RateLimit struct {
+	Enabled              bool    `yaml:"enabled"`
+	RequestsPerSecond    float64 `yaml:"requests_per_second"`
+	MinRequestsPerSecond float64 `yaml:"min_requests_per_second"`
+	Burst                int     `yaml:"burst"`
+	MaxRetries           int     `yaml:"max_retries"`
+	Backoff              Backoff `yaml:"backoff"`
+	Concurrency          int     `yaml:"concurrency"`
+}
+
+// Backoff - settings for exponential backoff (in milliseconds)
+type Backoff struct {
+	Base int `yaml:"base"`
+	Max  int `yaml:"max"`
+}
+
+// Waits - settings for waiting on chain progress (blocks, epochs and transaction confirmations)
+type Waits struct {
+	PollInterval         int    `yaml:"poll_interval"`
+	Timeout              int    `yaml:"timeout"`
+	StakingConfirmations uint64 `yaml:"staking_confirmations"`
+	CrossShardBlocks     uint64 `yaml:"cross_shard_blocks"`
+}
+
+// WebSocket - settings for confirming transactions using WebSocket subscriptions instead of polling receipts
+type WebSocket struct {
+	Enabled        bool              `yaml:"enabled"`
+	Endpoints      map[uint32]string `yaml:"endpoints"`
+	Pending        bool              `yaml:"pending"`
+	ConnectTimeout int               `yaml:"connect_timeout"`
+}
+
+// Snapshots - settings for the account state snapshots taken before and after every test case
+type Snapshots struct {
+	Enabled      bool        `yaml:"enabled"`
+	RawTolerance string      `yaml:"tolerance"`
+	Tolerance    numeric.Dec `yaml:"-"`
+}
+
 // Export - export settings
 type Export struct {
 	Path   string `yaml:"path"`
@@ -114,11 +226,176 @@ func (framework *Framework) Initialize() {
 	}
 }
 
+// Initialize - initializes the snapshot settings
+func (snapshots *Snapshots) Initialize() error {
+	snapshots.Tolerance = numeric.NewDec(0)
+	if snapshots.RawTolerance != "" {
+		decTolerance, err := common.NewDecFromString(snapshots.RawTolerance)
+		if err != nil {
+			return errors.Wrapf(err, "Snapshots: Tolerance")
+		}
+		snapshots.Tolerance = decTolerance
+	}
+
+	return nil
+}
+
+// StatePath - the path where state persisted across runs is stored for the current network
+func (config *Config) StatePath() string {
+	return filepath.Join(config.Framework.BasePath, "state", config.Network.Name)
+}
+
+// Initialize - initializes the wait settings
+func (waits *Waits) Initialize() {
+	if waits.PollInterval <= 0 {
+		waits.PollInterval = 1
+	}
+	if waits.Timeout <= 0 {
+		waits.Timeout = 120
+	}
+	if waits.CrossShardBlocks == 0 {
+		waits.CrossShardBlocks = 2
+	}
+}
+
+// Initialize - initializes the WebSocket settings
+func (webSocket *WebSocket) Initialize() {
+	if Args.WebSocket {
+		webSocket.Enabled = true
+	}
+	if webSocket.Endpoints == nil {
+		webSocket.Endpoints = make(map[uint32]string)
+	}
+	if webSocket.ConnectTimeout <= 0 {
+		webSocket.ConnectTimeout = 5
+	}
+}
+
+// Initialize - initializes the rate limit settings
+func (rateLimit *RateLimit) Initialize() {
+	if rateLimit.RequestsPerSecond <= 0 {
+		rateLimit.RequestsPerSecond = 10
+	}
+	if rateLimit.MinRequestsPerSecond <= 0 || rateLimit.MinRequestsPerSecond > rateLimit.RequestsPerSecond {
+		rateLimit.MinRequestsPerSecond = math.Min(1, rateLimit.RequestsPerSecond)
+	}
+	if rateLimit.Burst <= 0 {
+		rateLimit.Burst = int(math.Ceil(rateLimit.RequestsPerSecond))
+	}
+	if rateLimit.Backoff.Base <= 0 {
+		rateLimit.Backoff.Base = 250
+	}
+	if rateLimit.Backoff.Max < rateLimit.Backoff.Base {
+		rateLimit.Backoff.Max = 10000
+	}
+	if rateLimit.Concurrency <= 0 {
+		rateLimit.Concurrency = 10
+	}
+}
+
+// Initialize - initializes the cassette settings - cassettes are stored per network
+func (cassettes *Cassettes) Initialize() error {
+	if Args.Cassettes != "" {
+		cassettes.Mode = Args.Cassettes
+	}
+
+	cassettes.Mode = strings.ToLower(cassettes.Mode)
+	switch cassettes.Mode {
+	case "", "off", "record", "replay":
+	default:
+		return fmt.Errorf("invalid cassette mode %s - valid options: off, record, replay", cassettes.Mode)
+	}
+
+	if cassettes.Path == "" {
+		cassettes.Path = "cassettes"
+	}
+
+	if !filepath.IsAbs(cassettes.Path) {
+		cassettes.Path = filepath.Join(Configuration.Framework.BasePath, cassettes.Path)
+	}
+	cassettes.Path = filepath.Join(cassettes.Path, Configuration.Network.Name)
+
+	return nil
+}
+
+// Recording - whether or not JSON-RPC traffic should be recorded
+func (cassettes *Cassettes) Recording() bool {
+	return cassettes.Mode == "record"
+}
+
+// Replaying - whether or not JSON-RPC traffic should be answered from previously recorded cassettes
+func (cassettes *Cassettes) Replaying() bool {
+	return cassettes.Mode == "replay"
+}
+
+// ShardingStructurePath - the path of the recorded sharding structure used to set up the network when replaying
+func (cassettes *Cassettes) ShardingStructurePath() string {
+	return filepath.Join(cassettes.Path, "sharding_structure.json")
+}
+
+// RecordedShardSetup - sets up the shards using a previously recorded sharding structure instead of querying the network
+func (cassettes *Cassettes) RecordedShardSetup() (shards map[uint32]sdkNetworkTypes.Shard, shardingStructure []goSdkSharding.RPCRoutes, err error) {
+	data, err := ioutil.ReadFile(cassettes.ShardingStructurePath())
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to read the recorded sharding structure - record a run using --cassettes record first")
+	}
+
+	if err = json.Unmarshal(data, &shardingStructure); err != nil {
+		return nil, nil, err
+	}
+
+	shards = make(map[uint32]sdkNetworkTypes.Shard)
+	for _, route := range shardingStructure {
+		shards[uint32(route.ShardID)] = sdkNetworkTypes.Shard{Node: route.HTTP}
+	}
+
+	return shards, shardingStructure, nil
+}
+
 // CanExecuteMemoryIntensiveTestCase - whether or not certain test cases can be executed due to heavy memory consumption
 func (framework *Framework) CanExecuteMemoryIntensiveTestCase() bool {
 	return framework.SystemMemory >= framework.MinimumRequiredMemory
 }
 
+// Initialize - initializes account settings - the mnemonic is read from either an environment variable (env:NAME) or a file (file:path)
+func (account *Account) Initialize() error {
+	if account.QuarantineAfter <= 0 {
+		account.QuarantineAfter = 3
+	}
+
+	if account.RawMnemonic == "" {
+		return nil
+	}
+
+	switch {
+	case strings.HasPrefix(account.RawMnemonic, "env:"):
+		name := strings.TrimPrefix(account.RawMnemonic, "env:")
+		account.Mnemonic = strings.TrimSpace(os.Getenv(name))
+		if account.Mnemonic == "" {
+			return fmt.Errorf("Account: Mnemonic - the environment variable %s is empty", name)
+		}
+	case strings.HasPrefix(account.RawMnemonic, "file:"):
+		data, err := ioutil.ReadFile(strings.TrimPrefix(account.RawMnemonic, "file:"))
+		if err != nil {
+			return errors.Wrapf(err, "Account: Mnemonic")
+		}
+		account.Mnemonic = strings.TrimSpace(string(data))
+	default:
+		return errors.New("Account: Mnemonic - the mnemonic has to be read from an environment variable (env:NAME) or a file (file:path)")
+	}
+
+	if len(strings.Fields(account.Mnemonic)) < 12 {
+		return errors.New("Account: Mnemonic - the mnemonic has to consist of at least 12 words")
+	}
+
+	// Any string can be turned into a seed - a typo would otherwise silently derive a completely different set of accounts
+	if !bip39.IsMnemonicValid(account.Mnemonic) {
+		return errors.New("Account: Mnemonic - the mnemonic isn't a valid BIP39 mnemonic, please check it for typos")
+	}
+
+	return nil
+}
+
 // Initialize - initializes basic funding settings
 func (funding *Funding) Initialize() error {
 	if funding.RawMinimumFunds != "" {
@@ -129,6 +406,45 @@ func (funding *Funding) Initialize() error {
 		funding.MinimumFunds = decMinimumFunds
 	}
 
+	if funding.RawBudget != "" {
+		decBudget, err := common.NewDecFromString(funding.RawBudget)
+		if err != nil {
+			return errors.Wrapf(err, "Funding: Budget")
+		}
+		funding.Budget = decBudget
+	}
+
+	if funding.RawTarget != "" {
+		decTarget, err := common.NewDecFromString(funding.RawTarget)
+		if err != nil {
+			return errors.Wrapf(err, "Funding: Target")
+		}
+		funding.Target = decTarget
+	} else {
+		funding.Target = funding.MinimumFunds
+	}
+
+	funding.Conservation.Threshold = numeric.NewDec(0)
+	if funding.Conservation.RawThreshold != "" {
+		decThreshold, err := common.NewDecFromString(funding.Conservation.RawThreshold)
+		if err != nil {
+			return errors.Wrapf(err, "Funding: Conservation threshold")
+		}
+		funding.Conservation.Threshold = decThreshold
+	}
+
+	if len(funding.Sources) == 0 {
+		funding.Sources = []string{"local_keys"}
+	}
+
+	if funding.Faucet.Method == "" {
+		funding.Faucet.Method = "POST"
+	}
+
+	if funding.Faucet.MaxRequests <= 0 {
+		funding.Faucet.MaxRequests = 1
+	}
+
 	if err := funding.Gas.Initialize(); err != nil {
 		return err
 	}
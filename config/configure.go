@@ -16,6 +16,7 @@ import (
 	sdkNetworkTypes "github.com/harmony-one/go-lib/network/types/network"
 	sdkNetworkUtils "github.com/harmony-one/go-lib/network/utils"
 	goSdkCommon "github.com/harmony-one/go-sdk/pkg/common"
+	goSdkSharding "github.com/harmony-one/go-sdk/pkg/sharding"
 	"github.com/harmony-one/harmony-tf/utils"
 	"github.com/mackerelio/go-osstat/memory"
 	"gopkg.in/yaml.v2"
@@ -47,7 +48,9 @@ func Configure(basePath string) (err error) {
 		return err
 	}
 
-	configureAccountConfig()
+	if err = configureAccountConfig(); err != nil {
+		return err
+	}
 
 	if err = configureFundingConfig(); err != nil {
 		return err
@@ -79,14 +82,18 @@ func ConfigureStylingConfig() {
 	Configuration.Framework.Styling.Padding = strings.Repeat("\t", 10)
 }
 
-func configureNetworkConfig() error {
+func configureNetworkConfig() (err error) {
 	if Args.Network != "" && Args.Network != Configuration.Network.Name {
 		Configuration.Network.Name = Args.Network
 	}
 
-	Configuration.Network.Name = sdkNetworkUtils.NormalizedNetworkName(Configuration.Network.Name)
+	if err := initializeNetworkDefinitions(); err != nil {
+		return err
+	}
+
+	Configuration.Network.Name = resolveNetworkName(Configuration.Network.Name)
 	if Configuration.Network.Name == "" {
-		return errors.New("you need to specify a valid network name to use! Valid options: localnet, devnet, testnet, staking, stressnet, mainnet")
+		return errors.New("you need to specify a valid network name to use! Valid options: localnet, devnet, testnet, staking, stressnet, mainnet or a network defined in the networks section of config.yml")
 	}
 
 	Configuration.Network.Mode = strings.ToLower(Configuration.Network.Mode)
@@ -95,8 +102,20 @@ func configureNetworkConfig() error {
 		Configuration.Network.Mode = mode
 	}
 
+	customNetwork := Configuration.Network.CustomNetwork()
+	if customNetwork != nil {
+		// Node addresses of custom networks can't be generated from the network name - the defined endpoints are always used
+		Configuration.Network.Mode = "custom"
+		if Configuration.Network.Endpoints == nil {
+			Configuration.Network.Endpoints = make(map[string][]string)
+		}
+		Configuration.Network.Endpoints[Configuration.Network.Name] = customNetwork.AllEndpoints()
+	}
+
 	if len(Args.Nodes) > 0 {
 		Configuration.Network.Nodes = Args.Nodes
+	} else if customNetwork != nil {
+		Configuration.Network.Nodes = customNetwork.Nodes()
 	} else {
 		for networkType, nodes := range Configuration.Network.Endpoints {
 			if networkType == Configuration.Network.Name {
@@ -106,10 +125,34 @@ func configureNetworkConfig() error {
 		}
 	}
 
-	node := sdkNetworkUtils.ResolveStartingNode(Configuration.Network.Name, Configuration.Network.Mode, 0, Configuration.Network.Nodes)
-	shards, shardingStructure, err := sdkNetworkTypes.GenerateShardSetup(node, Configuration.Network.Name, Configuration.Network.Mode, Configuration.Network.Nodes)
-	if err != nil {
-		return fmt.Errorf("failed to generate network & shard setup for network %s using node %s - error: %s", Configuration.Network.Name, node, err.Error())
+	Configuration.Network.RateLimit.Initialize()
+	Configuration.Network.Waits.Initialize()
+	Configuration.Network.WebSocket.Initialize()
+	if customNetwork != nil {
+		for shardID, url := range customNetwork.WebSocketEndpoints {
+			if _, ok := Configuration.Network.WebSocket.Endpoints[shardID]; !ok {
+				Configuration.Network.WebSocket.Endpoints[shardID] = url
+			}
+		}
+	}
+
+	if err := Configuration.Network.Cassettes.Initialize(); err != nil {
+		return err
+	}
+
+	var shards map[uint32]sdkNetworkTypes.Shard
+	var shardingStructure []goSdkSharding.RPCRoutes
+	if Configuration.Network.Cassettes.Replaying() {
+		shards, shardingStructure, err = Configuration.Network.Cassettes.RecordedShardSetup()
+		if err != nil {
+			return err
+		}
+	} else {
+		node := sdkNetworkUtils.ResolveStartingNode(Configuration.Network.Name, Configuration.Network.Mode, 0, Configuration.Network.Nodes)
+		shards, shardingStructure, err = sdkNetworkTypes.GenerateShardSetup(node, Configuration.Network.Name, Configuration.Network.Mode, Configuration.Network.Nodes)
+		if err != nil {
+			return fmt.Errorf("failed to generate network & shard setup for network %s using node %s - error: %s", Configuration.Network.Name, node, err.Error())
+		}
 	}
 
 	if Configuration.Network.Mode == "api" {
@@ -135,11 +178,20 @@ func configureNetworkConfig() error {
 	}
 
 	Configuration.Network.API.Initialize()
+	Configuration.Network.StakingChainID = Configuration.Network.API.ChainID
+	if customNetwork != nil {
+		Configuration.Network.API.ChainID = customNetwork.ToChainID()
+		Configuration.Network.StakingChainID = customNetwork.ToStakingChainID()
+	}
 
 	if Configuration.Network.API.ChainID == nil {
 		return errors.New("chain id must be set - please check that you are using correct network settings")
 	}
 
+	if customNetwork != nil && customNetwork.Shards != len(shardingStructure) {
+		return fmt.Errorf("the network %s is defined with %d shard(s) but its sharding structure contains %d shard(s)", Configuration.Network.Name, customNetwork.Shards, len(shardingStructure))
+	}
+
 	Configuration.Network.Shards = len(shardingStructure)
 
 	if err := Configuration.Network.Gas.Initialize(); err != nil {
@@ -150,6 +202,11 @@ func configureNetworkConfig() error {
 		Configuration.Network.Timeout = Args.Timeout
 	}
 
+	// network.timeout and --timeout are explicitly supplied by the user and are therefore used as-is
+	Configuration.Network.TimeoutMultiplier = timeoutMultiplier(Configuration.Network.Name)
+	Configuration.Network.StakingWaitTime = uint32(Configuration.Network.AdjustTimeout(int(Configuration.Network.StakingWaitTime)))
+	Configuration.Network.Waits.Timeout = Configuration.Network.AdjustTimeout(Configuration.Network.Waits.Timeout)
+
 	return nil
 }
 
@@ -190,13 +247,15 @@ func configureFrameworkConfig() error {
 		Configuration.Framework.Test = testType
 	}
 
-	return nil
+	return Configuration.Framework.Snapshots.Initialize()
 }
 
-func configureAccountConfig() {
+func configureAccountConfig() error {
 	if Args.Passphrase != "" && Args.Passphrase != Configuration.Account.Passphrase {
 		Configuration.Account.Passphrase = Args.Passphrase
 	}
+
+	return Configuration.Account.Initialize()
 }
 
 func configureFundingConfig() error {
@@ -223,6 +282,7 @@ func configureFundingConfig() error {
 		return err
 	}
 
+	Configuration.Funding.Timeout = Configuration.Network.AdjustTimeout(Configuration.Funding.Timeout)
 	if Configuration.Network.Timeout > 0 && Configuration.Network.Timeout > Configuration.Funding.Timeout {
 		Configuration.Funding.Timeout = Configuration.Network.Timeout
 	}
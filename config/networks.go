@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	sdkNetworkUtils "github.com/harmony-one/go-lib/network/utils"
+	goSdkCommon "github.com/harmony-one/go-sdk/pkg/common"
+	"github.com/harmony-one/harmony-tf/utils"
+)
+
+// defaultTimeoutMultipliers - timeout multipliers for built-in networks that are known to be slower at processing txs and blocks
+var defaultTimeoutMultipliers = map[string]float64{
+	"localnet":  1.5,
+	"pangaea":   1.5,
+	"stressnet": 1.5,
+}
+
+// NetworkDefinition - a custom network that isn't one of the networks built into the Harmony SDK (e.g. private devnets or ephemeral PR networks)
+type NetworkDefinition struct {
+	Name               string              `yaml:"-"`
+	ChainID            int64               `yaml:"chain_id"`
+	StakingChainID     int64               `yaml:"staking_chain_id"`
+	Shards             int                 `yaml:"shards"`
+	Endpoints          map[uint32][]string `yaml:"endpoints"`
+	WebSocketEndpoints map[uint32]string   `yaml:"websocket_endpoints"`
+	TimeoutMultiplier  float64             `yaml:"timeout_multiplier"`
+}
+
+// Initialize - validates a network definition and applies its defaults
+func (definition *NetworkDefinition) Initialize(name string) error {
+	definition.Name = name
+
+	if definition.ChainID <= 0 {
+		return fmt.Errorf("networks: %s - chain_id must be set to a positive number", name)
+	}
+	if definition.StakingChainID <= 0 {
+		definition.StakingChainID = definition.ChainID
+	}
+	if definition.Shards <= 0 {
+		definition.Shards = len(definition.Endpoints)
+	}
+	if definition.Shards <= 0 {
+		// Endpoints can be omitted if the nodes are supplied using --nodes - one node per shard
+		definition.Shards = len(Args.Nodes)
+	}
+	if definition.TimeoutMultiplier <= 0 {
+		definition.TimeoutMultiplier = 1
+	}
+
+	// Endpoints can be omitted if the nodes are supplied using --nodes
+	if len(Args.Nodes) == 0 {
+		for shardID := uint32(0); shardID < uint32(definition.Shards); shardID++ {
+			if len(definition.Endpoints[shardID]) == 0 {
+				return fmt.Errorf("networks: %s - no endpoints defined for shard %d (the network has %d shard(s))", name, shardID, definition.Shards)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Nodes - the primary endpoint of every shard, ordered by shard id
+func (definition *NetworkDefinition) Nodes() (nodes []string) {
+	for shardID := uint32(0); shardID < uint32(definition.Shards); shardID++ {
+		if endpoints := definition.Endpoints[shardID]; len(endpoints) > 0 {
+			nodes = append(nodes, endpoints[0])
+		}
+	}
+
+	return nodes
+}
+
+// AllEndpoints - all endpoints of all shards
+func (definition *NetworkDefinition) AllEndpoints() (endpoints []string) {
+	shardIDs := []uint32{}
+	for shardID := range definition.Endpoints {
+		shardIDs = append(shardIDs, shardID)
+	}
+	sort.Slice(shardIDs, func(i, j int) bool { return shardIDs[i] < shardIDs[j] })
+
+	for _, shardID := range shardIDs {
+		endpoints = append(endpoints, definition.Endpoints[shardID]...)
+	}
+
+	return endpoints
+}
+
+// ToChainID - the chain id of the network
+func (definition *NetworkDefinition) ToChainID() *goSdkCommon.ChainID {
+	return &goSdkCommon.ChainID{Name: definition.Name, Value: big.NewInt(definition.ChainID)}
+}
+
+// ToStakingChainID - the chain id used to sign staking transactions on the network
+func (definition *NetworkDefinition) ToStakingChainID() *goSdkCommon.ChainID {
+	return &goSdkCommon.ChainID{Name: definition.Name, Value: big.NewInt(definition.StakingChainID)}
+}
+
+// CustomNetwork - the custom network definition of the current network (nil when using a built-in network)
+func (network *Network) CustomNetwork() *NetworkDefinition {
+	if definition, ok := Configuration.Networks[network.Name]; ok {
+		return definition
+	}
+
+	return nil
+}
+
+// AdjustTimeout - adjusts a timeout/wait time (in seconds) using the timeout multiplier of the current network
+func (network *Network) AdjustTimeout(timeout int) int {
+	return utils.NetworkTimeoutAdjustment(network.TimeoutMultiplier, timeout)
+}
+
+// resolveNetworkName - custom networks are used as-is, other network names are normalized to the names of the built-in networks
+func resolveNetworkName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if _, ok := Configuration.Networks[name]; ok {
+		return name
+	}
+
+	return sdkNetworkUtils.NormalizedNetworkName(name)
+}
+
+// initializeNetworkDefinitions - validates all custom network definitions - names are case insensitive
+func initializeNetworkDefinitions() error {
+	definitions := make(map[string]*NetworkDefinition)
+
+	for rawName, definition := range Configuration.Networks {
+		name := strings.ToLower(strings.TrimSpace(rawName))
+		if definition == nil {
+			return fmt.Errorf("networks: %s - the network definition is empty", rawName)
+		}
+		if err := definition.Initialize(name); err != nil {
+			return err
+		}
+		definitions[name] = definition
+	}
+
+	Configuration.Networks = definitions
+
+	return nil
+}
+
+func timeoutMultiplier(networkName string) float64 {
+	if definition, ok := Configuration.Networks[networkName]; ok {
+		return definition.TimeoutMultiplier
+	}
+
+	if multiplier, ok := defaultTimeoutMultipliers[networkName]; ok {
+		return multiplier
+	}
+
+	return 1
+}
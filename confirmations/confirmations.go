@@ -0,0 +1,117 @@
+package confirmations
+
+import (
+	"math"
+	"time"
+
+	sdkTxs "github.com/harmony-one/go-lib/transactions"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/network"
+)
+
+const (
+	// Transaction - regular (cross shard) transactions
+	Transaction = "transaction"
+
+	// Staking - staking transactions
+	Staking = "staking"
+)
+
+// Enabled - whether or not transactions are confirmed using WebSocket subscriptions
+// Cassette replays never contact the network and therefore always poll
+func Enabled() bool {
+	return config.Configuration.Network.WebSocket.Enabled && !network.Recorder.Replaying()
+}
+
+// SendTimeout - the timeout to pass to the SDK when sending a transaction - the SDK shouldn't poll receipts itself while transactions are confirmed using WebSocket subscriptions
+func SendTimeout(timeout int) int {
+	if Enabled() {
+		return 0
+	}
+
+	return timeout
+}
+
+// Result - waits for a sent transaction to be confirmed and returns its receipt - returns sent as-is if it wasn't confirmed within timeout seconds
+// Failed staking transactions are returned as-is as well, the SDK doesn't report them as errors either
+func Result(shardID uint32, txType string, sent map[string]interface{}, timeout int) (map[string]interface{}, error) {
+	txHash, _ := sent["transactionHash"].(string)
+	if txHash == "" || timeout <= 0 {
+		return sent, nil
+	}
+
+	receipt, err := Wait(shardID, txType, txHash, timeout)
+	if err != nil {
+		if txType == Staking {
+			return sent, nil
+		}
+		return nil, err
+	}
+
+	if receipt == nil {
+		return sent, nil
+	}
+
+	return receipt, nil
+}
+
+// Wait - waits until a given transaction has been included in a block and returns its receipt
+// Returns a nil receipt if the transaction wasn't confirmed within timeout seconds and an error if the transaction ended up in the error sink of the shard
+func Wait(shardID uint32, txType string, txHash string, timeout int) (map[string]interface{}, error) {
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+
+	if Enabled() {
+		if receipt, resolved, err := subscribe(shardID, txType, txHash, deadline); resolved {
+			return receipt, err
+		}
+	}
+
+	remaining := int(math.Ceil(time.Until(deadline).Seconds()))
+	if remaining <= 0 {
+		return nil, nil
+	}
+
+	return poll(shardID, txType, txHash, remaining)
+}
+
+// Close - closes all WebSocket subscriptions and waits until blocks that are still being checked have been processed
+func Close() {
+	subscribers.close()
+}
+
+// subscribe - waits for a transaction using the WebSocket subscription of its shard
+// resolved is false if no subscription could be established or if the subscription dropped - the caller should then poll for the remaining time
+func subscribe(shardID uint32, txType string, txHash string, deadline time.Time) (receipt map[string]interface{}, resolved bool, err error) {
+	subscriber, err := subscribers.get(shardID)
+	if err != nil {
+		return nil, false, nil
+	}
+
+	waiter := subscriber.watch(txType, txHash)
+	defer subscriber.unwatch(waiter)
+
+	// The transaction might already have been included or rejected before it was being watched
+	go subscriber.check(waiter)
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case result := <-waiter.result:
+		return result.receipt, true, result.err
+	case <-subscriber.closed:
+		return nil, false, nil
+	case <-timer.C:
+		return nil, true, nil
+	}
+}
+
+// poll - polls the receipt of a transaction every second using the SDK
+func poll(shardID uint32, txType string, txHash string, timeout int) (map[string]interface{}, error) {
+	rpcClient, err := config.Configuration.Network.API.RPCClient(shardID)
+	if err != nil {
+		return nil, err
+	}
+
+	return sdkTxs.WaitForTxConfirmation(rpcClient, config.Configuration.Network.API.NodeAddress(shardID), txType, txHash, timeout)
+}
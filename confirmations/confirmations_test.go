@@ -0,0 +1,219 @@
+package confirmations
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/go-sdk/pkg/address"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/mocknode"
+	"github.com/harmony-one/harmony-tf/mocknode/mocknodetest"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+const (
+	gasLimit = 21000
+	timeout  = 10
+)
+
+var chainID = big.NewInt(2)
+
+type signer struct {
+	key     *ecdsa.PrivateKey
+	address string
+}
+
+func newSigner(t *testing.T) signer {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signer{key: key, address: address.ToBech32(crypto.PubkeyToAddress(key.PublicKey))}
+}
+
+// startChain - starts a mock chain and confirms transactions using the WebSocket subscriptions it serves
+func startChain(t *testing.T, genesis map[string]numeric.Dec) *mocknode.Chain {
+	chain := mocknodetest.Start(t, mocknode.Options{ChainID: chainID, Shards: 1, BlockTime: 250 * time.Millisecond, Genesis: genesis})
+
+	config.Configuration.Network.WebSocket.Enabled = true
+	config.Configuration.Network.WebSocket.Endpoints = map[uint32]string{0: chain.WebSocketURLs[0]}
+
+	// The registry is shared by all tests - backoffs of subscriptions dropped by a previous test mustn't leak into the next one
+	subscribers.mutex.Lock()
+	subscribers.attempts = make(map[uint32]int)
+	subscribers.retryAt = make(map[uint32]time.Time)
+	subscribers.mutex.Unlock()
+	t.Cleanup(Close)
+
+	return chain
+}
+
+// subscribed - subscribes to the new heads of shard 0 and waits for the first head to arrive
+func subscribed(t *testing.T) *subscriber {
+	t.Helper()
+
+	subscriber, err := subscribers.get(0)
+	if err != nil {
+		t.Fatalf("failed to subscribe to new heads: %s", err.Error())
+	}
+
+	deadline := time.Now().Add(timeout * time.Second)
+	for !subscriber.receivingHeads() {
+		if time.Now().After(deadline) {
+			t.Fatal("didn't receive any new heads")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	return subscriber
+}
+
+// send - signs a transfer of a given amount of ONE in shard 0 and submits it to the mock chain
+func send(t *testing.T, chain *mocknode.Chain, from signer, nonce uint64, to string, amount int64) string {
+	t.Helper()
+
+	receiver := ethCommon.Address(address.Parse(to))
+	value := new(big.Int).Mul(big.NewInt(amount), big.NewInt(1e18))
+	tx := types.NewCrossShardTransaction(nonce, &receiver, 0, 0, value, gasLimit, big.NewInt(1e9), nil)
+
+	signed, err := types.SignTx(tx, types.NewEIP155Signer(chainID), from.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := chain.SendRawTransaction(0, hexutil.Encode(encoded))
+	if err != nil {
+		t.Fatalf("failed to send the transaction: %s", err.Error())
+	}
+
+	return hash.Hex()
+}
+
+// await - waits for the result handed to a waiter
+func await(t *testing.T, waiter *waiter) result {
+	t.Helper()
+
+	select {
+	case result := <-waiter.result:
+		return result
+	case <-time.After(timeout * time.Second):
+		t.Fatalf("transaction %s wasn't resolved", waiter.hash)
+		return result{}
+	}
+}
+
+func hasHash(receipt map[string]interface{}, txHash string) bool {
+	hash, _ := receipt["transactionHash"].(string)
+	return strings.EqualFold(hash, txHash)
+}
+
+func TestWaiterIsResolvedByNewHead(t *testing.T) {
+	sender, receiver := newSigner(t), newSigner(t)
+	chain := startChain(t, map[string]numeric.Dec{sender.address: numeric.NewDec(10)})
+	subscriber := subscribed(t)
+
+	// The waiter is registered before the transaction is sent and never checked directly - only a new head can resolve it
+	txHash := send(t, chain, sender, 1, receiver.address, 1)
+	waiter := subscriber.watch(Transaction, txHash)
+	defer subscriber.unwatch(waiter)
+	send(t, chain, sender, 0, receiver.address, 1)
+
+	result := await(t, waiter)
+	if result.err != nil {
+		t.Fatalf("expected the transaction to be confirmed, got: %s", result.err.Error())
+	}
+	if !hasHash(result.receipt, txHash) {
+		t.Errorf("expected the receipt of %s, got %v", txHash, result.receipt)
+	}
+}
+
+func TestWaitReturnsReceipt(t *testing.T) {
+	sender, receiver := newSigner(t), newSigner(t)
+	chain := startChain(t, map[string]numeric.Dec{sender.address: numeric.NewDec(10)})
+	subscribed(t)
+
+	txHash := send(t, chain, sender, 0, receiver.address, 1)
+	receipt, err := Result(0, Transaction, map[string]interface{}{"transactionHash": txHash}, timeout)
+	if err != nil {
+		t.Fatalf("expected the transaction to be confirmed, got: %s", err.Error())
+	}
+	if !hasHash(receipt, txHash) || receipt["blockNumber"] == nil {
+		t.Errorf("expected the receipt of %s, got %v", txHash, receipt)
+	}
+}
+
+func TestErrorSinkRejectionsAreReported(t *testing.T) {
+	sender, receiver := newSigner(t), newSigner(t)
+	chain := startChain(t, map[string]numeric.Dec{sender.address: numeric.NewDec(10)})
+	subscriber := subscribed(t)
+
+	if _, err := Wait(0, Transaction, send(t, chain, sender, 0, receiver.address, 1), timeout); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new head checks the error sinks for all waiting transactions
+	replayed := send(t, chain, sender, 0, receiver.address, 2)
+	waiter := subscriber.watch(Transaction, replayed)
+	defer subscriber.unwatch(waiter)
+	if result := await(t, waiter); result.err == nil || !strings.Contains(result.err.Error(), "nonce too low") {
+		t.Errorf("expected the rejection of %s to be reported, got %v", replayed, result.err)
+	}
+
+	if _, err := Result(0, Transaction, map[string]interface{}{"transactionHash": replayed}, timeout); err == nil {
+		t.Error("expected the result of a rejected transaction to be an error")
+	}
+}
+
+func TestWaitFallsBackToPollingWhenSubscriptionDrops(t *testing.T) {
+	sender, receiver := newSigner(t), newSigner(t)
+	chain := startChain(t, map[string]numeric.Dec{sender.address: numeric.NewDec(10)})
+	subscriber := subscribed(t)
+
+	// The transaction is queued behind a nonce gap so that it's still waiting when the subscription drops
+	txHash := send(t, chain, sender, 1, receiver.address, 1)
+
+	type confirmation struct {
+		receipt map[string]interface{}
+		err     error
+	}
+	confirmed := make(chan confirmation, 1)
+	go func() {
+		receipt, err := Wait(0, Transaction, txHash, timeout)
+		confirmed <- confirmation{receipt, err}
+	}()
+
+	time.Sleep(500 * time.Millisecond)
+	chain.DropSubscriptions()
+	select {
+	case <-subscriber.closed:
+	case <-time.After(timeout * time.Second):
+		t.Fatal("expected the subscriber to notice the dropped subscription")
+	}
+	send(t, chain, sender, 0, receiver.address, 1)
+
+	select {
+	case confirmation := <-confirmed:
+		if confirmation.err != nil {
+			t.Fatalf("expected the transaction to be confirmed by polling, got: %s", confirmation.err.Error())
+		}
+		if !hasHash(confirmation.receipt, txHash) {
+			t.Errorf("expected the receipt of %s, got %v", txHash, confirmation.receipt)
+		}
+	case <-time.After(2 * timeout * time.Second):
+		t.Fatal("the transaction wasn't confirmed after the subscription dropped")
+	}
+}
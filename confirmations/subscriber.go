@@ -0,0 +1,519 @@
+package confirmations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/websocket"
+	sdkRPC "github.com/harmony-one/go-lib/rpc"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/network"
+)
+
+const (
+	newHeads               = "newHeads"
+	newPendingTransactions = "newPendingTransactions"
+
+	// maxCatchUpBlocks - how many blocks are checked at most when new heads arrive with gaps (e.g. while the previous blocks were still being checked)
+	maxCatchUpBlocks = 10
+)
+
+var subscribers = &registry{
+	subscribers: make(map[uint32]*subscriber),
+	attempts:    make(map[uint32]int),
+	retryAt:     make(map[uint32]time.Time),
+}
+
+// registry - the WebSocket subscriptions of all shards
+type registry struct {
+	mutex       sync.Mutex
+	subscribers map[uint32]*subscriber
+	attempts    map[uint32]int
+	retryAt     map[uint32]time.Time
+}
+
+// get - the subscriber of a given shard - dropped subscriptions are re-established using backoff between failed attempts
+func (registry *registry) get(shardID uint32) (*subscriber, error) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if existing, ok := registry.subscribers[shardID]; ok {
+		if !existing.isClosed() {
+			if existing.receivingHeads() {
+				registry.attempts[shardID] = 0
+			}
+			return existing, nil
+		}
+
+		delete(registry.subscribers, shardID)
+		registry.retryAt[shardID] = existing.closedAt.Add(network.Backoff(registry.attempts[shardID]))
+		registry.attempts[shardID]++
+	}
+
+	if time.Now().Before(registry.retryAt[shardID]) {
+		return nil, fmt.Errorf("the WebSocket subscription of shard %d will be re-established at %s", shardID, registry.retryAt[shardID].Format(time.RFC3339))
+	}
+
+	address, err := endpoint(shardID)
+	var created *subscriber
+	if err == nil {
+		created, err = dial(shardID, address)
+	}
+	if err != nil {
+		registry.retryAt[shardID] = time.Now().Add(network.Backoff(registry.attempts[shardID]))
+		registry.attempts[shardID]++
+		logger.WarningLog(fmt.Sprintf("Failed to subscribe to new heads of shard %d - falling back to polling receipts: %s", shardID, err.Error()), true)
+		return nil, err
+	}
+
+	registry.subscribers[shardID] = created
+
+	return created, nil
+}
+
+func (registry *registry) close() {
+	registry.mutex.Lock()
+	closed := []*subscriber{}
+	for shardID, subscriber := range registry.subscribers {
+		subscriber.close()
+		closed = append(closed, subscriber)
+		delete(registry.subscribers, shardID)
+	}
+	registry.mutex.Unlock()
+
+	// Blocks that are still being checked would otherwise keep calling the nodes after the subscriptions have been closed
+	for _, subscriber := range closed {
+		<-subscriber.done
+	}
+}
+
+// subscriber - a WebSocket connection to a shard which is subscribed to new heads (and pending transactions where available)
+// Every new block is fetched once and resolves the waiters of all transactions included in it, regardless of how many transactions are waiting
+type subscriber struct {
+	shardID   uint32
+	url       string
+	conn      *websocket.Conn
+	heads     chan struct{}
+	closed    chan struct{}
+	done      chan struct{}
+	closedAt  time.Time
+	closeOnce sync.Once
+
+	mutex         sync.Mutex
+	waiters       map[string]*waiter
+	subscriptions map[string]string
+	head          uint64
+	lastBlock     uint64
+}
+
+type waiter struct {
+	txType   string
+	hash     string
+	included bool
+	pending  bool
+	result   chan result
+}
+
+type result struct {
+	receipt map[string]interface{}
+	err     error
+}
+
+type message struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	Params struct {
+		Subscription string          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	} `json:"params"`
+}
+
+// dial - connects to a given WebSocket endpoint and subscribes to new heads (and pending transactions if enabled)
+func dial(shardID uint32, address string) (*subscriber, error) {
+	dialer := websocket.Dialer{HandshakeTimeout: time.Duration(config.Configuration.Network.WebSocket.ConnectTimeout) * time.Second}
+	conn, _, err := dialer.Dial(address, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	kinds := []string{newHeads}
+	if config.Configuration.Network.WebSocket.Pending {
+		kinds = append(kinds, newPendingTransactions)
+	}
+
+	// Request ids are the index of the subscription kind + 1
+	for i, kind := range kinds {
+		request := map[string]interface{}{"jsonrpc": "2.0", "id": i + 1, "method": "hmy_subscribe", "params": []string{kind}}
+		if err := conn.WriteJSON(request); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	subscriber := &subscriber{
+		shardID:       shardID,
+		url:           address,
+		conn:          conn,
+		heads:         make(chan struct{}, 1),
+		closed:        make(chan struct{}),
+		done:          make(chan struct{}),
+		waiters:       make(map[string]*waiter),
+		subscriptions: make(map[string]string),
+	}
+
+	go subscriber.read(kinds)
+	go subscriber.process()
+
+	logger.Log(fmt.Sprintf("Subscribed to new heads of shard %d using %s", shardID, address), config.Configuration.Framework.Verbose)
+
+	return subscriber, nil
+}
+
+func (subscriber *subscriber) watch(txType string, txHash string) *waiter {
+	waiter := &waiter{txType: txType, hash: txHash, result: make(chan result, 1)}
+
+	subscriber.mutex.Lock()
+	subscriber.waiters[strings.ToLower(txHash)] = waiter
+	subscriber.mutex.Unlock()
+
+	return waiter
+}
+
+func (subscriber *subscriber) unwatch(waiter *waiter) {
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+
+	key := strings.ToLower(waiter.hash)
+	if subscriber.waiters[key] == waiter {
+		delete(subscriber.waiters, key)
+	}
+}
+
+func (subscriber *subscriber) receivingHeads() bool {
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+
+	return subscriber.head > 0
+}
+
+func (subscriber *subscriber) close() {
+	subscriber.closeOnce.Do(func() {
+		subscriber.closedAt = time.Now()
+		close(subscriber.closed)
+		subscriber.conn.Close()
+	})
+}
+
+func (subscriber *subscriber) isClosed() bool {
+	select {
+	case <-subscriber.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// read - handles subscription responses and notifications until the connection drops
+func (subscriber *subscriber) read(kinds []string) {
+	defer subscriber.close()
+
+	for {
+		var received message
+		if err := subscriber.conn.ReadJSON(&received); err != nil {
+			if !subscriber.isClosed() {
+				logger.WarningLog(fmt.Sprintf("The WebSocket subscription of shard %d (%s) dropped - falling back to polling receipts: %s", subscriber.shardID, subscriber.url, err.Error()), true)
+			}
+			return
+		}
+
+		switch {
+		case received.ID != nil && *received.ID > 0 && *received.ID <= len(kinds):
+			if !subscriber.subscribed(kinds[*received.ID-1], received) {
+				return
+			}
+		case strings.HasSuffix(received.Method, "_subscription"):
+			subscriber.notification(received.Params.Subscription, received.Params.Result)
+		}
+	}
+}
+
+// subscribed - handles the response to a subscription request - returns false if new heads can't be subscribed to
+func (subscriber *subscriber) subscribed(kind string, response message) bool {
+	if response.Error != nil {
+		if kind == newHeads {
+			logger.WarningLog(fmt.Sprintf("Failed to subscribe to new heads of shard %d (%s) - falling back to polling receipts: %s", subscriber.shardID, subscriber.url, response.Error.Message), true)
+			return false
+		}
+		logger.Log(fmt.Sprintf("Subscribing to %s isn't available for shard %d (%s): %s", kind, subscriber.shardID, subscriber.url, response.Error.Message), config.Configuration.Framework.Verbose)
+		return true
+	}
+
+	var subscription string
+	if err := json.Unmarshal(response.Result, &subscription); err == nil {
+		subscriber.mutex.Lock()
+		subscriber.subscriptions[subscription] = kind
+		subscriber.mutex.Unlock()
+	}
+
+	return true
+}
+
+func (subscriber *subscriber) notification(subscription string, raw json.RawMessage) {
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+
+	switch subscriber.subscriptions[subscription] {
+	case newHeads:
+		var header struct {
+			Number json.RawMessage `json:"number"`
+		}
+		if err := json.Unmarshal(raw, &header); err != nil || header.Number == nil {
+			return
+		}
+		number, err := network.ParseQuantity(header.Number)
+		if err != nil || number <= subscriber.head {
+			return
+		}
+		subscriber.head = number
+
+		// Heads arriving while blocks are still being checked are picked up by the next check
+		select {
+		case subscriber.heads <- struct{}{}:
+		default:
+		}
+	case newPendingTransactions:
+		var txHash string
+		if err := json.Unmarshal(raw, &txHash); err != nil {
+			return
+		}
+		if waiter, ok := subscriber.waiters[strings.ToLower(txHash)]; ok && !waiter.pending {
+			waiter.pending = true
+			logger.Log(fmt.Sprintf("Transaction %s is pending in shard %d", waiter.hash, subscriber.shardID), config.Configuration.Framework.Verbose)
+		}
+	}
+}
+
+func (subscriber *subscriber) process() {
+	defer close(subscriber.done)
+
+	for {
+		select {
+		case <-subscriber.heads:
+			subscriber.checkBlocks()
+		case <-subscriber.closed:
+			return
+		}
+	}
+}
+
+// checkBlocks - checks all blocks since the previous check - blocks are only fetched while transactions are waiting
+func (subscriber *subscriber) checkBlocks() {
+	subscriber.mutex.Lock()
+	head := subscriber.head
+	from := subscriber.lastBlock + 1
+	if subscriber.lastBlock == 0 {
+		from = head
+	}
+	subscriber.lastBlock = head
+	waiting := len(subscriber.waiters) > 0
+	subscriber.mutex.Unlock()
+
+	if !waiting || head < from {
+		return
+	}
+	if head-from >= maxCatchUpBlocks {
+		from = head - maxCatchUpBlocks + 1
+	}
+
+	for number := from; number <= head; number++ {
+		subscriber.checkBlock(number)
+	}
+
+	for _, waiter := range subscriber.waiting(true) {
+		subscriber.checkReceipt(waiter)
+	}
+
+	subscriber.checkFailures(subscriber.waiting(false))
+}
+
+// check - checks whether a single transaction has already been included or rejected
+func (subscriber *subscriber) check(pending *waiter) {
+	subscriber.checkReceipt(pending)
+	subscriber.checkFailures([]*waiter{pending})
+}
+
+// checkBlock - marks the waiters of all (staking) transactions included in a given block - all waiters are marked if the block can't be fetched
+func (subscriber *subscriber) checkBlock(number uint64) {
+	var block struct {
+		Transactions        []json.RawMessage `json:"transactions"`
+		StakingTransactions []json.RawMessage `json:"stakingTransactions"`
+	}
+
+	raw, err := network.Call(config.Configuration.Network.API.NodeAddress(subscriber.shardID), "hmy_getBlockByNumber", hexutil.EncodeUint64(number), false)
+	if err == nil {
+		err = json.Unmarshal(raw, &block)
+	}
+
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+
+	if err != nil {
+		for _, waiter := range subscriber.waiters {
+			waiter.included = true
+		}
+		return
+	}
+
+	for _, tx := range append(block.Transactions, block.StakingTransactions...) {
+		if waiter, ok := subscriber.waiters[strings.ToLower(transactionHash(tx))]; ok {
+			waiter.included = true
+		}
+	}
+}
+
+// checkReceipt - resolves a waiter if the receipt of its transaction is available
+func (subscriber *subscriber) checkReceipt(waiter *waiter) {
+	raw, err := network.Call(config.Configuration.Network.API.NodeAddress(subscriber.shardID), "hmy_getTransactionReceipt", waiter.hash)
+	if err != nil {
+		return
+	}
+
+	var receipt map[string]interface{}
+	if err := json.Unmarshal(raw, &receipt); err != nil || receipt == nil {
+		return
+	}
+
+	waiter.resolve(receipt, nil)
+}
+
+// checkFailures - resolves the waiters of transactions that ended up in the error sinks of the shard
+func (subscriber *subscriber) checkFailures(waiters []*waiter) {
+	node := config.Configuration.Network.API.NodeAddress(subscriber.shardID)
+
+	failures := make(map[string][]sdkRPC.Failure)
+	for _, waiter := range waiters {
+		if _, ok := failures[waiter.txType]; ok {
+			continue
+		}
+
+		var err error
+		if waiter.txType == Staking {
+			failures[waiter.txType], err = sdkRPC.StakingFailures(node)
+		} else {
+			failures[waiter.txType], err = sdkRPC.TransactionFailures(node)
+		}
+		if err != nil {
+			failures[waiter.txType] = nil
+		}
+	}
+
+	for _, waiter := range waiters {
+		if failure, failed := sdkRPC.FailureOccurredForTransaction(failures[waiter.txType], waiter.hash); failed {
+			waiter.resolve(nil, errors.New(failure.ErrorMessage))
+		}
+	}
+}
+
+// waiting - all current waiters - or only the waiters of transactions that have been included in a block
+func (subscriber *subscriber) waiting(includedOnly bool) (waiters []*waiter) {
+	subscriber.mutex.Lock()
+	defer subscriber.mutex.Unlock()
+
+	for _, waiter := range subscriber.waiters {
+		if !includedOnly || waiter.included {
+			waiters = append(waiters, waiter)
+		}
+	}
+
+	return waiters
+}
+
+// resolve - hands the result to the waiting transaction - only the first result is used
+func (waiter *waiter) resolve(receipt map[string]interface{}, err error) {
+	select {
+	case waiter.result <- result{receipt: receipt, err: err}:
+	default:
+	}
+}
+
+// transactionHash - the hash of a transaction listed in a block - blocks either list hashes or full transactions
+func transactionHash(raw json.RawMessage) string {
+	var txHash string
+	if err := json.Unmarshal(raw, &txHash); err == nil {
+		return txHash
+	}
+
+	var tx struct {
+		Hash string `json:"hash"`
+	}
+	json.Unmarshal(raw, &tx)
+
+	return tx.Hash
+}
+
+// endpoint - the WebSocket endpoint of a given shard: the configured endpoint, the ws route of the sharding structure or an endpoint derived from the shard's current HTTP endpoint
+func endpoint(shardID uint32) (string, error) {
+	if address := config.Configuration.Network.WebSocket.Endpoints[shardID]; address != "" {
+		return address, nil
+	}
+
+	for _, route := range config.Configuration.Network.API.ShardingStructure {
+		if uint32(route.ShardID) == shardID && route.WS != "" {
+			return route.WS, nil
+		}
+	}
+
+	node := network.ShardEndpoint(shardID)
+	if node == "" {
+		return "", fmt.Errorf("no endpoint found for shard %d", shardID)
+	}
+
+	return webSocketURL(node)
+}
+
+// webSocketURL - derives the WebSocket endpoint of a node from its HTTP endpoint using the conventions of Harmony nodes - api.* hosts are served by ws.* hosts and port 95xx by port 98xx
+func webSocketURL(node string) (string, error) {
+	parsed, err := url.Parse(node)
+	if err != nil {
+		return "", err
+	}
+
+	switch parsed.Scheme {
+	case "http":
+		parsed.Scheme = "ws"
+	case "https":
+		parsed.Scheme = "wss"
+	case "ws", "wss":
+	default:
+		return "", fmt.Errorf("can't derive a WebSocket endpoint from %s", node)
+	}
+
+	host, port := parsed.Hostname(), parsed.Port()
+	if strings.HasPrefix(host, "api.") {
+		host = "ws." + strings.TrimPrefix(host, "api.")
+	}
+
+	parsed.Host = host
+	if port != "" {
+		if number, err := strconv.Atoi(port); err == nil && number >= 9500 && number < 9600 {
+			port = strconv.Itoa(number + 300)
+		}
+		parsed.Host = net.JoinHostPort(host, port)
+	}
+
+	return parsed.String(), nil
+}
@@ -0,0 +1,205 @@
+package conservation
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	sdkAccounts "github.com/harmony-one/go-lib/accounts"
+	sdkDelegation "github.com/harmony-one/go-lib/staking/delegation"
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/balances"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/transactions"
+	"github.com/harmony-one/harmony-tf/utils"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+// Suite - the fund conservation check of the current test suite run
+var Suite = &Check{}
+
+// Check - reconciles the funds held by the funding account and the source keys at the start and the end of a test suite run
+// The difference should be explained by the gas spent, the stake still locked in validators/delegations and the funds stranded in generated accounts
+type Check struct {
+	mutex        sync.Mutex
+	started      bool
+	finished     bool
+	startGas     numeric.Dec
+	Addresses    []string
+	Start        numeric.Dec
+	End          numeric.Dec
+	Gas          numeric.Dec
+	EstimatedGas int
+	Locked       numeric.Dec
+	Stranded     numeric.Dec
+	Errors       []string
+}
+
+// Begin - sums the balances of the funding account and the given source keys in all shards
+func Begin(sources []sdkAccounts.Account) {
+	if !config.Configuration.Funding.Conservation.Enabled {
+		return
+	}
+
+	addresses := []string{config.Configuration.Funding.Account.Address}
+	for _, source := range sources {
+		if source.Address != "" && !utils.StringSliceContains(addresses, source.Address) {
+			addresses = append(addresses, source.Address)
+		}
+	}
+
+	gas, _ := transactions.Gas.Total()
+
+	Suite.mutex.Lock()
+	defer Suite.mutex.Unlock()
+
+	Suite.Addresses = addresses
+	Suite.startGas = gas
+	Suite.Start = Suite.sum(addresses)
+	Suite.started = true
+}
+
+// Finish - sums the balances of the funding account and the source keys again and determines where the difference went
+func Finish() {
+	Suite.mutex.Lock()
+	defer Suite.mutex.Unlock()
+
+	if !Suite.started || Suite.finished {
+		return
+	}
+
+	gas, estimated := transactions.Gas.Total()
+	Suite.Gas = gas.Sub(Suite.startGas)
+	Suite.EstimatedGas = estimated
+	Suite.End = Suite.sum(Suite.Addresses)
+
+	Suite.Locked = numeric.NewDec(0)
+	Suite.Stranded = numeric.NewDec(0)
+	for _, account := range accounts.GeneratedAccounts() {
+		if utils.StringSliceContains(Suite.Addresses, account.Address) {
+			continue
+		}
+
+		Suite.Stranded = Suite.Stranded.Add(Suite.sum([]string{account.Address}))
+		Suite.Locked = Suite.Locked.Add(Suite.locked(account.Address))
+	}
+
+	Suite.finished = true
+}
+
+// Finished - whether or not the check has been performed
+func (check *Check) Finished() bool {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	return check.finished
+}
+
+// Completed - whether or not both sums could be determined without errors
+func (check *Check) Completed() bool {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	return check.completed()
+}
+
+// Loss - how much less the funding account and the source keys hold at the end of the run
+func (check *Check) Loss() numeric.Dec {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	return check.loss()
+}
+
+// Unexplained - the part of the loss that can't be explained by gas, locked stake or stranded funds
+func (check *Check) Unexplained() numeric.Dec {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	return check.unexplained()
+}
+
+// Failed - whether or not the unexplained loss exceeds funding.conservation.threshold - this is a framework error rather than a test case failure
+func (check *Check) Failed() bool {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	return check.completed() && check.unexplained().GT(config.Configuration.Funding.Conservation.Threshold)
+}
+
+// Report - formats the reconciliation of the current run
+func (check *Check) Report() string {
+	check.mutex.Lock()
+	defer check.mutex.Unlock()
+
+	var builder strings.Builder
+	format := "%-45s %s\n"
+	builder.WriteString(fmt.Sprintf(format, "Accounts (funding account + source keys):", fmt.Sprintf("%d", len(check.Addresses))))
+	builder.WriteString(fmt.Sprintf(format, "Balance at start:", fmt.Sprintf("%f", check.Start)))
+	builder.WriteString(fmt.Sprintf(format, "Balance at end:", fmt.Sprintf("%f", check.End)))
+	builder.WriteString(fmt.Sprintf(format, "Difference:", fmt.Sprintf("%f", check.loss())))
+	builder.WriteString(fmt.Sprintf(format, "Gas spent:", fmt.Sprintf("%f (%d transaction(s) estimated using their gas limit)", check.Gas, check.EstimatedGas)))
+	builder.WriteString(fmt.Sprintf(format, "Stake locked in validators/delegations:", fmt.Sprintf("%f", check.Locked)))
+	builder.WriteString(fmt.Sprintf(format, "Stranded in generated accounts:", fmt.Sprintf("%f", check.Stranded)))
+	builder.WriteString(fmt.Sprintf(format, "Unexplained:", fmt.Sprintf("%f (threshold: %f)", check.unexplained(), config.Configuration.Funding.Conservation.Threshold)))
+
+	for _, err := range check.Errors {
+		builder.WriteString(fmt.Sprintf(format, "Error:", err))
+	}
+
+	return builder.String()
+}
+
+// completed, loss, unexplained - the lock has to be held
+func (check *Check) completed() bool {
+	return check.finished && len(check.Errors) == 0
+}
+
+func (check *Check) loss() numeric.Dec {
+	return check.Start.Sub(check.End)
+}
+
+func (check *Check) unexplained() numeric.Dec {
+	return check.loss().Sub(check.Gas).Sub(check.Locked).Sub(check.Stranded)
+}
+
+// sum - sums the balances of the given addresses in all shards - the lock has to be held
+func (check *Check) sum(addresses []string) numeric.Dec {
+	total := numeric.NewDec(0)
+
+	for _, address := range addresses {
+		for shard := 0; shard < config.Configuration.Network.Shards; shard++ {
+			balance, err := balances.GetShardBalance(address, uint32(shard))
+			if err != nil {
+				check.Errors = append(check.Errors, fmt.Sprintf("failed to retrieve the balance of %s in shard %d: %s", address, shard, err.Error()))
+				continue
+			}
+
+			if !balance.IsNil() {
+				total = total.Add(balance)
+			}
+		}
+	}
+
+	return total
+}
+
+// locked - the amount delegated (or still being undelegated) by a given address - the lock has to be held
+func (check *Check) locked(address string) numeric.Dec {
+	total := numeric.NewDec(0)
+
+	delegations, err := sdkDelegation.ByDelegator(config.Configuration.Network.API.NodeAddress(0), address)
+	if err != nil {
+		check.Errors = append(check.Errors, fmt.Sprintf("failed to retrieve the delegations of %s: %s", address, err.Error()))
+		return total
+	}
+
+	for _, delegation := range delegations {
+		total = total.Add(balances.FromAtto(delegation.RawAmount))
+		for _, undelegation := range delegation.Undelegations {
+			total = total.Add(balances.FromAtto(undelegation.RawAmount))
+		}
+	}
+
+	return total
+}
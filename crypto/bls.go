@@ -1,10 +1,14 @@
 package crypto
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 
+	bls_core "github.com/harmony-one/bls/ffi/go/bls"
 	sdkCrypto "github.com/harmony-one/go-lib/crypto"
 	"github.com/harmony-one/harmony-tf/config"
+	harmonyTypes "github.com/harmony-one/harmony/staking/types"
 )
 
 // GenerateBlsKeys - generates a set of bls keys given a count
@@ -43,6 +47,74 @@ func GenerateBlsKey(shardID uint32, message string) (blsKey sdkCrypto.BLSKey, er
 	return blsKey, nil
 }
 
+// RestoreBlsKey - restores a bls key using its private key (hex) and signs the given message
+func RestoreBlsKey(privateKeyHex string, message string) (blsKey sdkCrypto.BLSKey, err error) {
+	privateKey := &bls_core.SecretKey{}
+	if err := privateKey.DeserializeHexStr(privateKeyHex); err != nil {
+		return sdkCrypto.BLSKey{}, err
+	}
+
+	publicKey := privateKey.GetPublicKey()
+	blsKey = sdkCrypto.BLSKey{
+		PrivateKey:    privateKey,
+		PrivateKeyHex: privateKey.SerializeToHexStr(),
+		PublicKey:     publicKey,
+		PublicKeyHex:  publicKey.SerializeToHexStr(),
+	}
+
+	if err := blsKey.Initialize(message); err != nil {
+		return sdkCrypto.BLSKey{}, err
+	}
+
+	return blsKey, nil
+}
+
+// GenerateBlsKeyForDifferentShard - generates a new bls key that doesn't belong to the given shard
+func GenerateBlsKeyForDifferentShard(shardID uint32, message string) (blsKey sdkCrypto.BLSKey, err error) {
+	if config.Configuration.Network.Shards < 2 {
+		return sdkCrypto.BLSKey{}, errors.New("can't generate a bls key for a different shard when the network only has a single shard")
+	}
+
+	for {
+		blsKey, err = sdkCrypto.GenerateBlsKey(message)
+		if err != nil {
+			return sdkCrypto.BLSKey{}, err
+		}
+
+		if !blsKeyMatchesShardID(blsKey, shardID) {
+			break
+		}
+	}
+
+	return blsKey, nil
+}
+
+// GenerateBlsKeyWithWrongMessage - generates a new bls key for the given shard with a signature made over a different message than the expected message
+func GenerateBlsKeyWithWrongMessage(shardID uint32, message string) (blsKey sdkCrypto.BLSKey, err error) {
+	if message == "" {
+		message = harmonyTypes.BLSVerificationStr
+	}
+
+	return GenerateBlsKey(shardID, fmt.Sprintf("invalid-%s", message))
+}
+
+// GenerateBlsKeyWithCorruptedSignature - generates a new bls key for the given shard and corrupts its signature
+func GenerateBlsKeyWithCorruptedSignature(shardID uint32, message string) (blsKey sdkCrypto.BLSKey, err error) {
+	blsKey, err = GenerateBlsKey(shardID, message)
+	if err != nil {
+		return sdkCrypto.BLSKey{}, err
+	}
+
+	// Copy the signature before modifying it so that the original signature isn't mutated
+	corruptedSignature := *blsKey.ShardSignature
+	for i := range corruptedSignature {
+		corruptedSignature[i] ^= 0xff
+	}
+	blsKey.ShardSignature = &corruptedSignature
+
+	return blsKey, nil
+}
+
 func blsKeyMatchesShardID(blsKey sdkCrypto.BLSKey, desiredShardID uint32) bool {
 	bigShardCount := big.NewInt(int64(config.Configuration.Network.Shards))
 	resolvedShardID := int(new(big.Int).Mod(blsKey.ShardPublicKey.Big(), bigShardCount).Int64())
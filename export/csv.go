@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/conservation"
+	"github.com/harmony-one/harmony-tf/metrics"
+	"github.com/harmony-one/harmony-tf/network"
+	"github.com/harmony-one/harmony-tf/snapshots"
 	"github.com/harmony-one/harmony-tf/testing"
 	"github.com/harmony-one/harmony-tf/utils"
 )
@@ -25,6 +30,8 @@ var (
 		"Started At",
 		"Finished At",
 		"Duration",
+		"Teardown Warnings",
+		"Unexpected State Changes",
 	}
 )
 
@@ -62,12 +69,83 @@ func ExportCSV(results []*testing.TestCase, dismissed []*testing.TestCase, faile
 		}
 	}
 
+	if rows := stateChangeRows(results); len(rows) > 0 {
+		records = append(records, emptyRow())
+		records = append(records, emptyRow())
+		records = append(records, titleRow("State Changes:"))
+		records = append(records, padRow([]string{"Test Case", "Account", "Address", "Value", "Before", "After", "Unexpected", "Reason"}, "append"))
+		records = append(records, rows...)
+	}
+
+	if hashes, nodes := network.TransactionNodes.All(); len(hashes) > 0 {
+		records = append(records, emptyRow())
+		records = append(records, emptyRow())
+		records = append(records, titleRow("Transaction Nodes:"))
+		records = append(records, padRow([]string{"Transaction Hash", "Node"}, "append"))
+
+		for i, hash := range hashes {
+			records = append(records, padRow([]string{hash, nodes[i]}, "append"))
+		}
+	}
+
+	if stats := metrics.RPC.Stats(); len(stats) > 0 {
+		records = append(records, emptyRow())
+		records = append(records, emptyRow())
+		records = append(records, titleRow("RPC Calls:"))
+		records = append(records, padRow([]string{"Method", "Endpoint", "Outcome", "Calls", "Avg", "p50", "p95", "Min", "Max", "Total", "Latency Histogram"}, "append"))
+
+		for _, callStats := range stats {
+			records = append(records, padRow([]string{
+				callStats.Method,
+				callStats.Endpoint,
+				callStats.Outcome,
+				fmt.Sprintf("%d", callStats.Count),
+				callStats.Average().String(),
+				callStats.Percentile(50).String(),
+				callStats.Percentile(95).String(),
+				callStats.Min.String(),
+				callStats.Max.String(),
+				callStats.Total.String(),
+				callStats.HistogramString(),
+			}, "append"))
+		}
+	}
+
+	if stats := metrics.Sleeps.Stats(); len(stats) > 0 {
+		records = append(records, emptyRow())
+		records = append(records, emptyRow())
+		records = append(records, titleRow("Framework Sleeps:"))
+		records = append(records, padRow([]string{"Reason", "Sleeps", "Total"}, "append"))
+
+		for _, sleepStats := range stats {
+			records = append(records, padRow([]string{sleepStats.Reason, fmt.Sprintf("%d", sleepStats.Count), sleepStats.Total.String()}, "append"))
+		}
+	}
+
+	if conservation.Suite.Finished() {
+		records = append(records, emptyRow())
+		records = append(records, emptyRow())
+		records = append(records, titleRow("Fund Conservation:"))
+		records = append(records, summaryRow("Balance at start:", fmt.Sprintf("%f", conservation.Suite.Start)))
+		records = append(records, summaryRow("Balance at end:", fmt.Sprintf("%f", conservation.Suite.End)))
+		records = append(records, summaryRow("Gas spent:", fmt.Sprintf("%f", conservation.Suite.Gas)))
+		records = append(records, summaryRow("Stake locked:", fmt.Sprintf("%f", conservation.Suite.Locked)))
+		records = append(records, summaryRow("Stranded:", fmt.Sprintf("%f", conservation.Suite.Stranded)))
+		records = append(records, summaryRow("Unexplained:", fmt.Sprintf("%f", conservation.Suite.Unexplained())))
+		for _, err := range conservation.Suite.Errors {
+			records = append(records, summaryRow("Error:", err))
+		}
+	}
+
 	records = append(records, emptyRow())
 	records = append(records, emptyRow())
 	records = append(records, summaryRow("Summary:", ""))
 	records = append(records, summaryRow("Successful:", fmt.Sprintf("%d", successfulCount)))
 	records = append(records, summaryRow("Failed:", fmt.Sprintf("%d", failedCount)))
 	records = append(records, summaryRow("Dismissed:", fmt.Sprintf("%d", len(dismissed))))
+	if conservation.Suite.Failed() {
+		records = append(records, summaryRow("Framework Errors:", fmt.Sprintf("%f of the funds held by the funding account and the source keys can't be accounted for", conservation.Suite.Unexplained())))
+	}
 	records = append(records, emptyRow())
 	records = append(records, summaryRow("Duration:", totalDuration.String()))
 
@@ -107,9 +185,43 @@ func csvRow(testCase *testing.TestCase) []string {
 		startedAtString,
 		finishedAtString,
 		durationString,
+		strings.Join(testCase.Warnings, "; "),
+		strings.Join(testCase.Snapshot.Unexpected(), "; "),
 	}
 }
 
+// stateChangeRows - the before/after diffs of all accounts touched by the executed test cases
+func stateChangeRows(results []*testing.TestCase) (rows [][]string) {
+	for _, result := range results {
+		if result.Snapshot == nil {
+			continue
+		}
+
+		for _, account := range result.Snapshot.Accounts {
+			for _, change := range account.Changes {
+				rows = append(rows, padRow([]string{
+					result.Name,
+					account.Name,
+					account.Address,
+					change.Field,
+					change.Before,
+					change.After,
+					fmt.Sprintf("%t", change.Unexpected),
+					change.Reason,
+				}, "append"))
+			}
+
+			for _, state := range []*snapshots.State{account.Before, account.After} {
+				for _, err := range state.Errors {
+					rows = append(rows, padRow([]string{result.Name, account.Name, account.Address, "(not captured)", "", "", "", err}, "append"))
+				}
+			}
+		}
+	}
+
+	return rows
+}
+
 func dismissedRow(testCase *testing.TestCase) []string {
 	return padRow(
 		[]string{
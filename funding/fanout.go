@@ -0,0 +1,158 @@
+package funding
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	sdkAccounts "github.com/harmony-one/go-lib/accounts"
+	sdkNetworkNonce "github.com/harmony-one/go-lib/network/rpc/nonces"
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/balances"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony/numeric"
+	"github.com/pkg/errors"
+)
+
+var (
+	subFundersMutex sync.Mutex
+	subFunders      = make(map[uint32][]sdkAccounts.Account)
+)
+
+// FanOutEnabled - whether or not a given number of accounts should be funded using sub-funders
+func FanOutEnabled(count int64) bool {
+	return config.Configuration.Funding.FanOut.SubFunders > 0 && count >= config.Configuration.Funding.FanOut.Threshold
+}
+
+// fanOutGenerateAndFundAccounts - generates a set of accounts and funds them in parallel using the sub-funders of the given shard
+// The funding account only funds (or tops up) the sub-funders, each sub-funder then funds its share of the accounts using its own nonce sequence
+func fanOutGenerateAndFundAccounts(count int64, nameTemplate string, amount numeric.Dec, fromShardID uint32, toShardID uint32) (accs []sdkAccounts.Account, err error) {
+	funderCount := int64(config.Configuration.Funding.FanOut.SubFunders)
+	if count < funderCount {
+		funderCount = count
+	}
+
+	share := (count + funderCount - 1) / funderCount
+	perFunderAmount := amount.Add(config.Configuration.Funding.Gas.Cost).Mul(numeric.NewDec(share))
+
+	funders, err := prepareSubFunders(fromShardID, int(funderCount), perFunderAmount)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Sub-funders")
+	}
+
+	rpcClient, err := config.Configuration.Network.API.RPCClient(fromShardID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "RPC Client")
+	}
+
+	var waitGroup sync.WaitGroup
+	accountsChannel := make(chan sdkAccounts.Account, count)
+
+	for i := int64(0); i < count; i++ {
+		funder := &funders[i%funderCount]
+		if i < funderCount {
+			// Each sub-funder has its own nonce sequence - fetch it once and increment it for every subsequent account funded by the same sub-funder
+			funder.Nonce = sdkNetworkNonce.CurrentNonce(rpcClient, funder.Account.Address)
+		}
+
+		waitGroup.Add(1)
+		go generateAndFundAccountFrom(&funder.Account, i, nameTemplate, fromShardID, toShardID, amount, int(funder.Nonce), accountsChannel, &waitGroup)
+		funder.Nonce++
+	}
+
+	waitGroup.Wait()
+	close(accountsChannel)
+
+	for acc := range accountsChannel {
+		accs = append(accs, acc)
+	}
+
+	return accs, nil
+}
+
+type subFunder struct {
+	Account sdkAccounts.Account
+	Nonce   uint64
+}
+
+// prepareSubFunders - generates the sub-funders for a given shard if they don't exist yet and tops them up so that each one of them holds at least the required amount
+func prepareSubFunders(shardID uint32, count int, requiredAmount numeric.Dec) ([]subFunder, error) {
+	subFundersMutex.Lock()
+	defer subFundersMutex.Unlock()
+
+	for i := len(subFunders[shardID]); i < count; i++ {
+		account, err := subFunderAccount(accounts.GenerateTestCaseAccountName("SubFunders", fmt.Sprintf("Shard%d_%d", shardID, i)))
+		if err != nil {
+			return nil, err
+		}
+		subFunders[shardID] = append(subFunders[shardID], account)
+		Ledger.AddSubFunder(account.Address)
+	}
+
+	funders := []subFunder{}
+	for _, account := range subFunders[shardID][:count] {
+		funders = append(funders, subFunder{Account: account})
+
+		balance, err := balances.GetShardBalance(account.Address, shardID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !balance.IsNil() && balance.GTE(requiredAmount) {
+			continue
+		}
+
+		topUp := requiredAmount
+		if !balance.IsNil() {
+			topUp = requiredAmount.Sub(balance)
+		}
+
+		// The funding account only has a single nonce sequence - the sub-funders are therefore topped up sequentially
+		logger.FundingLog(fmt.Sprintf("Topping up sub-funder %s in shard %d with %f", account.Address, shardID, topUp), config.Configuration.Funding.Verbose)
+		if err := PerformFundingTransaction(&config.Configuration.Funding.Account, shardID, account.Address, shardID, topUp, -1, config.Configuration.Funding.Gas.Limit, config.Configuration.Funding.Gas.Price, config.Configuration.Funding.Timeout, config.Configuration.Funding.Retry.Attempts); err != nil {
+			return nil, err
+		}
+	}
+
+	return funders, nil
+}
+
+// subFunderAccount - reuses a sub-funder left behind in the keystore by a previous run (e.g. one that crashed before its teardown) or generates a new one
+// Sub-funder names are deterministic and a left behind sub-funder might still hold funds - it's therefore never replaced
+func subFunderAccount(name string) (sdkAccounts.Account, error) {
+	if existing := sdkAccounts.FindAccountByName(name); existing.Address != "" {
+		logger.FundingLog(fmt.Sprintf("Reusing sub-funder %s, address: %s left behind in the keystore by a previous run", name, existing.Address), config.Configuration.Funding.Verbose)
+		existing.Passphrase = config.Configuration.Account.Passphrase
+		accounts.Register(existing)
+		return existing, nil
+	}
+
+	return accounts.GenerateAccount(name)
+}
+
+// ReclaimSubFunders - returns the funds held by all sub-funders to the funding account and removes them from the keystore once their final balances have been verified
+func ReclaimSubFunders() {
+	subFundersMutex.Lock()
+	defer subFundersMutex.Unlock()
+
+	for shardID, funders := range subFunders {
+		for i := range funders {
+			account := &funders[i]
+			logger.TeardownLog(fmt.Sprintf("Returning the funds of sub-funder %s in shard %d to the funding account", account.Address, shardID), config.Configuration.Funding.Verbose)
+
+			if failures := ReturnFunds(account, config.Configuration.Funding.Account.Address); len(failures) > 0 {
+				err := fmt.Errorf("failed to reclaim the funds of sub-funder %s, address: %s - keeping it in the keystore (%s)", account.Name, account.Address, strings.Join(failures, ", "))
+				if persistErr := accounts.Persist(account); persistErr != nil {
+					err = fmt.Errorf("%s - failed to persist the in-memory account to the keystore: %s", err.Error(), persistErr.Error())
+				}
+				logger.WarningLog(err.Error(), true)
+				continue
+			}
+
+			accounts.RemoveAccount(account)
+		}
+	}
+
+	subFunders = make(map[uint32][]sdkAccounts.Account)
+}
@@ -0,0 +1,127 @@
+package funding
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/metrics"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+// FaucetSource - funds the funding account by requesting funds from an HTTP faucet
+type FaucetSource struct {
+	Settings    config.Faucet
+	Client      *http.Client
+	mutex       sync.Mutex
+	lastRequest time.Time
+	requests    map[uint32]int
+}
+
+// NewFaucetSource - creates a new faucet funding source
+func NewFaucetSource(settings config.Faucet) *FaucetSource {
+	return &FaucetSource{
+		Settings: settings,
+		Client:   &http.Client{Timeout: 30 * time.Second},
+		requests: make(map[uint32]int),
+	}
+}
+
+// Name - the name of the funding source
+func (source *FaucetSource) Name() string {
+	return "faucet"
+}
+
+// TopUp - requests funds from the faucet until the funding account has received the required amount or faucet.max_requests has been reached
+func (source *FaucetSource) TopUp(shardID uint32, required numeric.Dec) error {
+	received := numeric.NewDec(0)
+
+	for received.LT(required) {
+		if source.requests[shardID] >= source.Settings.MaxRequests {
+			return fmt.Errorf("reached the maximum of %d faucet request(s) for shard %d - received %f out of %f", source.Settings.MaxRequests, shardID, received, required)
+		}
+
+		before, err := fundingAccountBalance(shardID)
+		if err != nil {
+			return err
+		}
+
+		if err := source.Request(shardID, required.Sub(received)); err != nil {
+			return err
+		}
+
+		// Faucets usually don't report what they sent - any increase of the balance counts as received funds
+		if err := waitForTopUp(shardID, before, numeric.NewDecWithPrec(1, 18), time.Duration(config.Configuration.Funding.Timeout)*time.Second); err != nil {
+			return err
+		}
+
+		after, err := fundingAccountBalance(shardID)
+		if err != nil {
+			return err
+		}
+		received = received.Add(after.Sub(before))
+		logger.FundingLog(fmt.Sprintf("Received %f from the faucet in shard %d", after.Sub(before), shardID), true)
+	}
+
+	return nil
+}
+
+// Request - performs a single faucet request for the funding account in the given shard, respecting faucet.interval between requests
+func (source *FaucetSource) Request(shardID uint32, amount numeric.Dec) error {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	interval := time.Duration(source.Settings.Interval) * time.Second
+	if wait := interval - time.Since(source.lastRequest); !source.lastRequest.IsZero() && wait > 0 {
+		logger.FundingLog(fmt.Sprintf("Waiting %v before the next faucet request", wait), true)
+		metrics.Sleep("faucet interval", wait)
+	}
+
+	source.lastRequest = time.Now()
+	source.requests[shardID]++
+
+	replacer := strings.NewReplacer(
+		"{address}", config.Configuration.Funding.Account.Address,
+		"{shard}", fmt.Sprintf("%d", shardID),
+		"{amount}", fmt.Sprintf("%f", amount),
+	)
+
+	method := strings.ToUpper(source.Settings.Method)
+	url := replacer.Replace(source.Settings.URL)
+
+	var request *http.Request
+	var err error
+	if method == http.MethodGet {
+		request, err = http.NewRequest(method, url, nil)
+	} else {
+		request, err = http.NewRequest(method, url, strings.NewReader(replacer.Replace(source.Settings.Body)))
+		if err == nil {
+			request.Header.Set("Content-Type", "application/json")
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	for name, value := range source.Settings.Headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := source.Client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, _ := ioutil.ReadAll(response.Body)
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("the faucet responded with status %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package funding
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/harmony-one/harmony-tf/balances"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/mocknode"
+	"github.com/harmony-one/harmony-tf/mocknode/mocknodetest"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+const faucetTestAddress = "one1cyf2h38vgd4d6hqg45pxtzmh4x3ywzz800mde9"
+
+// startFaucet - starts a stand-in faucet that credits the mock chain before responding, i.e. the funds have already arrived once the request returns
+func startFaucet(t *testing.T, chain *mocknode.Chain, amount numeric.Dec, status int) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if status != http.StatusOK {
+			http.Error(writer, "faucet is dry", status)
+			return
+		}
+		if err := chain.Fund(request.URL.Query().Get("address"), 0, amount); err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writer.Write([]byte(`{"success":true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func faucetSettings(url string, maxRequests int) config.Faucet {
+	return config.Faucet{
+		URL:         url + "?address={address}&shard={shard}&amount={amount}",
+		Method:      http.MethodGet,
+		MaxRequests: maxRequests,
+	}
+}
+
+func setupFaucetTest(t *testing.T) *mocknode.Chain {
+	chain := mocknodetest.Start(t, mocknode.Options{ChainID: big.NewInt(2), Shards: 1})
+	config.Configuration.Funding.Account.Address = faucetTestAddress
+	config.Configuration.Funding.Timeout = 5
+
+	return chain
+}
+
+func TestFaucetTopUpCountsFundsArrivingBeforeTheWait(t *testing.T) {
+	chain := setupFaucetTest(t)
+	server, requests := startFaucet(t, chain, numeric.NewDec(10), http.StatusOK)
+
+	source := NewFaucetSource(faucetSettings(server.URL, 1))
+	if err := source.TopUp(0, numeric.NewDec(10)); err != nil {
+		t.Fatalf("expected the top up to succeed, got: %s", err.Error())
+	}
+
+	if count := atomic.LoadInt32(requests); count != 1 {
+		t.Errorf("expected 1 faucet request, got %d", count)
+	}
+
+	balance, err := balances.GetShardBalance(faucetTestAddress, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !balance.Equal(numeric.NewDec(10)) {
+		t.Errorf("expected a balance of 10, got %f", balance)
+	}
+}
+
+func TestFaucetTopUpRequestsUntilTheRequiredAmountHasBeenReceived(t *testing.T) {
+	chain := setupFaucetTest(t)
+	server, requests := startFaucet(t, chain, numeric.NewDec(4), http.StatusOK)
+
+	source := NewFaucetSource(faucetSettings(server.URL, 3))
+	if err := source.TopUp(0, numeric.NewDec(10)); err != nil {
+		t.Fatalf("expected the top up to succeed, got: %s", err.Error())
+	}
+
+	if count := atomic.LoadInt32(requests); count != 3 {
+		t.Errorf("expected 3 faucet requests, got %d", count)
+	}
+}
+
+func TestFaucetTopUpStopsAtMaxRequests(t *testing.T) {
+	chain := setupFaucetTest(t)
+	server, requests := startFaucet(t, chain, numeric.NewDec(4), http.StatusOK)
+
+	source := NewFaucetSource(faucetSettings(server.URL, 2))
+	if err := source.TopUp(0, numeric.NewDec(10)); err == nil {
+		t.Fatal("expected the top up to fail after reaching the maximum number of requests")
+	}
+
+	if count := atomic.LoadInt32(requests); count != 2 {
+		t.Errorf("expected 2 faucet requests, got %d", count)
+	}
+}
+
+func TestFaucetRequestReportsErrorResponses(t *testing.T) {
+	chain := setupFaucetTest(t)
+	server, _ := startFaucet(t, chain, numeric.NewDec(4), http.StatusServiceUnavailable)
+
+	source := NewFaucetSource(faucetSettings(server.URL, 1))
+	if err := source.TopUp(0, numeric.NewDec(10)); err == nil {
+		t.Fatal("expected the top up to fail when the faucet responds with an error")
+	}
+}
@@ -0,0 +1,352 @@
+package funding
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/balances"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/transactions"
+	"github.com/harmony-one/harmony-tf/utils"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+var (
+	// Ledger - keeps track of all funding transfers and teardown returns performed during the current run
+	Ledger = NewLedger()
+
+	// ErrBudgetExceeded - returned when a funding transfer would make the net funding cost exceed the configured budget
+	ErrBudgetExceeded = errors.New("funding budget exceeded")
+
+	setupLabel      = "setup"
+	subFundersLabel = "sub-funders"
+)
+
+// LedgerEntry - represents a funding transfer or a teardown return
+type LedgerEntry struct {
+	TestCase        string
+	Type            string
+	FromAddress     string
+	FromShardID     uint32
+	ToAddress       string
+	ToShardID       uint32
+	Amount          numeric.Dec
+	GasCost         numeric.Dec
+	GasCostActual   bool
+	TransactionHash string
+	Time            time.Time
+}
+
+// LedgerSummary - represents the funding cost for a given test case
+type LedgerSummary struct {
+	TestCase   string
+	Funded     numeric.Dec
+	Returned   numeric.Dec
+	FundingGas numeric.Dec
+	Gas        numeric.Dec
+	Leaked     numeric.Dec
+}
+
+// Net - the net cost of the test case - gas spent by generated accounts is already included since it's paid using funded amounts
+func (summary *LedgerSummary) Net() numeric.Dec {
+	return summary.Funded.Add(summary.FundingGas).Sub(summary.Returned)
+}
+
+// FundingLedger - records funding transfers and teardown returns per test case and shard
+// Top ups and reclaims of sub-funders are attributed to the sub-funders themselves, the transfers from sub-funders to generated accounts to the test case they were performed for
+type FundingLedger struct {
+	mutex      sync.Mutex
+	testCase   string
+	reserved   numeric.Dec
+	exhausted  bool
+	subFunders map[string]bool
+	Entries    []LedgerEntry
+	Accounts   map[string]LedgerAccount
+}
+
+// LedgerAccount - represents an account that has been funded during the current run
+type LedgerAccount struct {
+	Address  string
+	ShardID  uint32
+	TestCase string
+}
+
+// NewLedger - creates a new funding ledger
+func NewLedger() *FundingLedger {
+	return &FundingLedger{
+		reserved:   numeric.NewDec(0),
+		subFunders: make(map[string]bool),
+		Accounts:   make(map[string]LedgerAccount),
+	}
+}
+
+// AddSubFunder - registers a sub-funder so that transfers from it to generated accounts are recorded as fan-out transfers
+func (ledger *FundingLedger) AddSubFunder(address string) {
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	ledger.subFunders[address] = true
+}
+
+// IsSubFunder - whether or not a given address belongs to a registered sub-funder
+func (ledger *FundingLedger) IsSubFunder(address string) bool {
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	return ledger.subFunders[address]
+}
+
+// SetTestCase - sets the test case subsequent transfers will be attributed to
+func (ledger *FundingLedger) SetTestCase(name string) {
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	ledger.testCase = name
+}
+
+// Reserve - reserves an amount that is about to be transferred from the funding account including the maximum gas cost of the transfer
+// Returns the reserved amount (which has to be released using Release) or ErrBudgetExceeded if the reservation would exceed the configured budget
+func (ledger *FundingLedger) Reserve(amount numeric.Dec, gasLimit int64, gasPrice numeric.Dec) (numeric.Dec, error) {
+	gasCost, _ := transactions.GasCost(nil, gasLimit, gasPrice)
+	reservation := amount.Add(gasCost)
+
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	budget := config.Configuration.Funding.Budget
+	if !budget.IsNil() && budget.IsPositive() {
+		projected := ledger.netCost().Add(ledger.reserved).Add(reservation)
+		if projected.GT(budget) {
+			ledger.exhausted = true
+			return numeric.NewDec(0), fmt.Errorf("%w: transferring %f (plus %f gas) would bring the net funding cost to %f which is more than the budget of %f", ErrBudgetExceeded, amount, gasCost, projected, budget)
+		}
+	}
+
+	ledger.reserved = ledger.reserved.Add(reservation)
+
+	return reservation, nil
+}
+
+// Release - releases a previously reserved amount
+func (ledger *FundingLedger) Release(amount numeric.Dec) {
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	ledger.reserved = ledger.reserved.Sub(amount)
+}
+
+// BudgetExhausted - whether or not a funding transfer has been refused due to the configured budget
+func (ledger *FundingLedger) BudgetExhausted() bool {
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	return ledger.exhausted
+}
+
+// RecordFunding - records a funding transfer from the funding account to a generated account
+func (ledger *FundingLedger) RecordFunding(fromAddress string, fromShardID uint32, toAddress string, toShardID uint32, amount numeric.Dec, rawTx map[string]interface{}, gasLimit int64, gasPrice numeric.Dec) {
+	ledger.record("funding", fromAddress, fromShardID, toAddress, toShardID, amount, rawTx, gasLimit, gasPrice)
+}
+
+// RecordFanOut - records a funding transfer from a sub-funder to a generated account
+// The funds already left the funding account when the sub-funder was topped up - fan-out transfers therefore don't add to the net cost, they only move it from the sub-funders to the test case
+func (ledger *FundingLedger) RecordFanOut(fromAddress string, fromShardID uint32, toAddress string, toShardID uint32, amount numeric.Dec, rawTx map[string]interface{}, gasLimit int64, gasPrice numeric.Dec) {
+	ledger.record("fan-out", fromAddress, fromShardID, toAddress, toShardID, amount, rawTx, gasLimit, gasPrice)
+}
+
+// RecordReturn - records funds being returned from a generated account during teardown
+func (ledger *FundingLedger) RecordReturn(fromAddress string, fromShardID uint32, toAddress string, toShardID uint32, amount numeric.Dec, rawTx map[string]interface{}, gasLimit int64, gasPrice numeric.Dec) {
+	ledger.record("return", fromAddress, fromShardID, toAddress, toShardID, amount, rawTx, gasLimit, gasPrice)
+}
+
+func (ledger *FundingLedger) record(entryType string, fromAddress string, fromShardID uint32, toAddress string, toShardID uint32, amount numeric.Dec, rawTx map[string]interface{}, gasLimit int64, gasPrice numeric.Dec) {
+	gasCost, gasCostActual := transactions.GasCost(rawTx, gasLimit, gasPrice)
+
+	txHash := ""
+	if rawTx != nil {
+		txHash, _ = rawTx["transactionHash"].(string)
+	}
+
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	testCase := ledger.testCase
+	if testCase == "" {
+		testCase = setupLabel
+	}
+	if entryType != "fan-out" && (ledger.subFunders[fromAddress] || ledger.subFunders[toAddress]) {
+		testCase = subFundersLabel
+	}
+
+	ledger.Entries = append(ledger.Entries, LedgerEntry{
+		TestCase:        testCase,
+		Type:            entryType,
+		FromAddress:     fromAddress,
+		FromShardID:     fromShardID,
+		ToAddress:       toAddress,
+		ToShardID:       toShardID,
+		Amount:          amount,
+		GasCost:         gasCost,
+		GasCostActual:   gasCostActual,
+		TransactionHash: txHash,
+		Time:            time.Now().UTC(),
+	})
+
+	if entryType == "funding" || entryType == "fan-out" {
+		if _, ok := ledger.Accounts[toAddress]; !ok {
+			ledger.Accounts[toAddress] = LedgerAccount{Address: toAddress, ShardID: toShardID, TestCase: testCase}
+		}
+	}
+}
+
+// FundedAccounts - the addresses of all accounts that were first funded during a given test case
+func (ledger *FundingLedger) FundedAccounts(testCase string) (addresses []string) {
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	for _, account := range ledger.Accounts {
+		if account.TestCase == testCase {
+			addresses = append(addresses, account.Address)
+		}
+	}
+
+	return addresses
+}
+
+// NetCost - the total net cost of all recorded transfers
+func (ledger *FundingLedger) NetCost() numeric.Dec {
+	ledger.mutex.Lock()
+	defer ledger.mutex.Unlock()
+
+	return ledger.netCost()
+}
+
+// Fan-out transfers are skipped - their funds were already counted when the sub-funders were topped up
+func (ledger *FundingLedger) netCost() numeric.Dec {
+	cost := numeric.NewDec(0)
+
+	for _, entry := range ledger.Entries {
+		switch entry.Type {
+		case "funding":
+			cost = cost.Add(entry.Amount).Add(entry.GasCost)
+		case "return":
+			cost = cost.Sub(entry.Amount)
+		}
+	}
+
+	return cost
+}
+
+// Summaries - summarizes the recorded transfers per test case and checks for leaked funds still held by funded accounts
+// Accounts listed in excludedAddresses (e.g. reused validators) won't be checked for leaked funds
+func (ledger *FundingLedger) Summaries(excludedAddresses []string) (summaries []LedgerSummary, totalGas numeric.Dec) {
+	ledger.mutex.Lock()
+	entries := make([]LedgerEntry, len(ledger.Entries))
+	copy(entries, ledger.Entries)
+	accounts := []LedgerAccount{}
+	for _, account := range ledger.Accounts {
+		accounts = append(accounts, account)
+	}
+	ledger.mutex.Unlock()
+
+	summaryMap := make(map[string]*LedgerSummary)
+	summaryFor := func(testCase string) *LedgerSummary {
+		summary, ok := summaryMap[testCase]
+		if !ok {
+			summary = &LedgerSummary{
+				TestCase:   testCase,
+				Funded:     numeric.NewDec(0),
+				Returned:   numeric.NewDec(0),
+				FundingGas: numeric.NewDec(0),
+				Gas:        numeric.NewDec(0),
+				Leaked:     numeric.NewDec(0),
+			}
+			summaryMap[testCase] = summary
+		}
+		return summary
+	}
+
+	totalGas = numeric.NewDec(0)
+	for _, entry := range entries {
+		summary := summaryFor(entry.TestCase)
+		switch entry.Type {
+		case "funding":
+			summary.Funded = summary.Funded.Add(entry.Amount)
+			summary.FundingGas = summary.FundingGas.Add(entry.GasCost)
+		case "fan-out":
+			summary.Funded = summary.Funded.Add(entry.Amount)
+			summary.FundingGas = summary.FundingGas.Add(entry.GasCost)
+
+			// The sub-funders passed these funds on - only the gas of their own top ups and reclaims remains as their cost
+			subFundersSummary := summaryFor(subFundersLabel)
+			subFundersSummary.Returned = subFundersSummary.Returned.Add(entry.Amount).Add(entry.GasCost)
+		case "return":
+			summary.Returned = summary.Returned.Add(entry.Amount)
+		}
+		summary.Gas = summary.Gas.Add(entry.GasCost)
+		totalGas = totalGas.Add(entry.GasCost)
+	}
+
+	for _, account := range accounts {
+		if utils.StringSliceContains(excludedAddresses, account.Address) {
+			continue
+		}
+
+		// Funds can leak into any shard, e.g. when a test case performs cross shard transfers
+		for shard := 0; shard < config.Configuration.Network.Shards; shard++ {
+			balance, err := balances.GetShardBalance(account.Address, uint32(shard))
+			if err == nil && !balance.IsNil() && balance.IsPositive() {
+				summary := summaryFor(account.TestCase)
+				summary.Leaked = summary.Leaked.Add(balance)
+			}
+		}
+	}
+
+	for _, summary := range summaryMap {
+		summaries = append(summaries, *summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].TestCase < summaries[j].TestCase
+	})
+
+	return summaries, totalGas
+}
+
+// Report - formats a funding cost report for the current run
+func (ledger *FundingLedger) Report(excludedAddresses []string) string {
+	summaries, totalGas := ledger.Summaries(excludedAddresses)
+
+	var builder strings.Builder
+	format := "%-50s %-20s %-20s %-20s %-20s %s\n"
+	builder.WriteString(fmt.Sprintf(format, "Test case", "Funded", "Returned", "Gas", "Net cost", "Leaked"))
+	builder.WriteString(fmt.Sprintf("%s\n", strings.Repeat("-", 50)))
+
+	totalNet := numeric.NewDec(0)
+	totalLeaked := numeric.NewDec(0)
+	for _, summary := range summaries {
+		net := summary.Net()
+		totalNet = totalNet.Add(net)
+		totalLeaked = totalLeaked.Add(summary.Leaked)
+
+		builder.WriteString(fmt.Sprintf(format,
+			summary.TestCase,
+			fmt.Sprintf("%f", summary.Funded),
+			fmt.Sprintf("%f", summary.Returned),
+			fmt.Sprintf("%f", summary.Gas),
+			fmt.Sprintf("%f", net),
+			fmt.Sprintf("%f", summary.Leaked),
+		))
+	}
+
+	builder.WriteString(fmt.Sprintf("%s\n", strings.Repeat("-", 50)))
+	builder.WriteString(fmt.Sprintf("Total net cost: %f\n", totalNet))
+	builder.WriteString(fmt.Sprintf("Total gas: %f\n", totalGas))
+	builder.WriteString(fmt.Sprintf("Total leaked funds (still held by generated accounts): %f\n", totalLeaked))
+
+	return builder.String()
+}
@@ -0,0 +1,77 @@
+package funding
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/harmony-one/harmony-tf/mocknode"
+	"github.com/harmony-one/harmony-tf/mocknode/mocknodetest"
+	"github.com/harmony-one/harmony-tf/transactions"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+const (
+	ledgerFundingAddress   = "one1cyf2h38vgd4d6hqg45pxtzmh4x3ywzz800mde9"
+	ledgerSubFunderAddress = "one1pdv9lrdwl0rg5vglh4xtyrv3wjk3wsqket7zxy"
+	ledgerAccountAddress   = "one1spshr72utf6rwxseaz339j09ed8p6f8ke370zj"
+	ledgerGasLimit         = int64(21000)
+)
+
+func summaryOf(t *testing.T, summaries []LedgerSummary, testCase string) LedgerSummary {
+	t.Helper()
+
+	for _, summary := range summaries {
+		if summary.TestCase == testCase {
+			return summary
+		}
+	}
+	t.Fatalf("no summary for %s in %+v", testCase, summaries)
+
+	return LedgerSummary{}
+}
+
+func TestFanOutTransfersAreAttributedToTheActiveTestCase(t *testing.T) {
+	mocknodetest.Start(t, mocknode.Options{ChainID: big.NewInt(2), Shards: 1})
+
+	ledger := NewLedger()
+	gasPrice := numeric.NewDec(1)
+	gas, _ := transactions.GasCost(nil, ledgerGasLimit, gasPrice)
+
+	// The sub-funder is topped up while the first test case is running but funds an account of the second one
+	ledger.AddSubFunder(ledgerSubFunderAddress)
+	ledger.SetTestCase("first")
+	ledger.RecordFunding(ledgerFundingAddress, 0, ledgerSubFunderAddress, 0, numeric.NewDec(10), nil, ledgerGasLimit, gasPrice)
+	ledger.SetTestCase("second")
+	ledger.RecordFanOut(ledgerSubFunderAddress, 0, ledgerAccountAddress, 0, numeric.NewDec(4), nil, ledgerGasLimit, gasPrice)
+	ledger.RecordReturn(ledgerAccountAddress, 0, ledgerFundingAddress, 0, numeric.NewDec(3), nil, ledgerGasLimit, gasPrice)
+	ledger.SetTestCase("")
+	ledger.RecordReturn(ledgerSubFunderAddress, 0, ledgerFundingAddress, 0, numeric.NewDec(5), nil, ledgerGasLimit, gasPrice)
+
+	if funded := ledger.FundedAccounts("second"); len(funded) != 1 || funded[0] != ledgerAccountAddress {
+		t.Errorf("expected the fanned out account to be funded by the second test case, got %v", funded)
+	}
+	if funded := ledger.FundedAccounts("first"); len(funded) != 0 {
+		t.Errorf("expected the first test case not to fund any accounts, got %v", funded)
+	}
+
+	summaries, _ := ledger.Summaries(nil)
+	for _, summary := range summaries {
+		if summary.TestCase == "first" {
+			t.Errorf("expected the sub-funder top up not to be attributed to the first test case, got %+v", summary)
+		}
+	}
+
+	second := summaryOf(t, summaries, "second")
+	if net, expected := second.Net(), numeric.NewDec(1).Add(gas); !net.Equal(expected) {
+		t.Errorf("expected the second test case to cost %f, got %f", expected, net)
+	}
+
+	// The costs per test case (including the sub-funders) add up to the net cost the budget is checked against
+	total := numeric.NewDec(0)
+	for i := range summaries {
+		total = total.Add(summaries[i].Net())
+	}
+	if expected := numeric.NewDec(10).Add(gas).Sub(numeric.NewDec(3)).Sub(numeric.NewDec(5)); !ledger.NetCost().Equal(expected) || !total.Equal(expected) {
+		t.Errorf("expected a net cost of %f, got %f in total and %f summed up per test case", expected, ledger.NetCost(), total)
+	}
+}
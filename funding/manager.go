@@ -2,7 +2,6 @@ package funding
 
 import (
 	"fmt"
-	"strconv"
 	"sync"
 
 	sdkAccounts "github.com/harmony-one/go-lib/accounts"
@@ -27,7 +26,7 @@ func SetupFundingAccount(accs []sdkAccounts.Account) (err error) {
 				config.Configuration.Funding.Account.Address = resolvedAddress
 			}
 		} else {
-			config.Configuration.Funding.Account, err = accounts.GenerateAccount(config.Configuration.Funding.Account.Name)
+			config.Configuration.Funding.Account, err = accounts.GenerateKeystoreAccount(config.Configuration.Funding.Account.Name)
 
 			if err != nil {
 				return err
@@ -37,11 +36,13 @@ func SetupFundingAccount(accs []sdkAccounts.Account) (err error) {
 
 	config.Configuration.Funding.Account.Unlock()
 
-	if len(accs) > 0 {
-		logger.FundingLog(fmt.Sprintf("Proceeding to fund funding acccount %s / %s with a total of %d source accounts...", config.Configuration.Funding.Account.Name, config.Configuration.Funding.Account.Address, len(accs)), true)
-		if err := FundFundingAccount(accs); err != nil {
-			logger.ErrorLog(fmt.Sprintf("Proceeding to fund funding acccount %s / %s - error: %s", config.Configuration.Funding.Account.Name, config.Configuration.Funding.Account.Address, err.Error()), true)
-		}
+	sources, err := ConfiguredSources(accs)
+	if err != nil {
+		return err
+	}
+
+	if err := TopUpFundingAccount(sources); err != nil {
+		logger.ErrorLog(fmt.Sprintf("Failed to top up funding acccount %s / %s - error: %s", config.Configuration.Funding.Account.Name, config.Configuration.Funding.Account.Address, err.Error()), true)
 	}
 
 	for shardID := range config.Configuration.Network.API.Shards {
@@ -83,30 +84,6 @@ func SetupFundingAccount(accs []sdkAccounts.Account) (err error) {
 	return nil
 }
 
-// FundFundingAccount - funds the funding account using the specified source accounts
-func FundFundingAccount(accs []sdkAccounts.Account) error {
-	var waitGroup sync.WaitGroup
-
-	for _, account := range accs {
-		if config.Configuration.Funding.Shards == "all" {
-			for shard := 0; shard < config.Configuration.Network.Shards; shard++ {
-				FundFundingAccountInShard(account, uint32(shard), &waitGroup)
-			}
-		} else {
-			shard, err := strconv.ParseUint(config.Configuration.Funding.Shards, 10, 32)
-			if err != nil {
-				return err
-			}
-
-			FundFundingAccountInShard(account, uint32(shard), &waitGroup)
-		}
-	}
-
-	waitGroup.Wait()
-
-	return nil
-}
-
 // FundFundingAccountInShard - fund the funding account in a given shard
 func FundFundingAccountInShard(account sdkAccounts.Account, shard uint32, waitGroup *sync.WaitGroup) {
 	availableShardBalance, err := balances.GetShardBalance(account.Address, shard)
@@ -162,6 +139,15 @@ func fundFundingAccountInNonBeaconShard(shardID uint32) (amount numeric.Dec, err
 
 // GenerateAndFundAccounts - generate and fund a set of accounts
 func GenerateAndFundAccounts(count int64, nameTemplate string, amount numeric.Dec, fromShardID uint32, toShardID uint32) (accs []sdkAccounts.Account, err error) {
+	if FanOutEnabled(count) {
+		amount, err = CalculateFundingAmount(amount, 1)
+		if err != nil {
+			return accs, errors.Wrapf(err, "Calculate Funding Amount")
+		}
+
+		return fanOutGenerateAndFundAccounts(count, nameTemplate, amount, fromShardID, toShardID)
+	}
+
 	rpcClient, err := config.Configuration.Network.API.RPCClient(fromShardID)
 	if err != nil {
 		return nil, errors.Wrapf(err, "RPC Client")
@@ -189,7 +175,7 @@ func GenerateAndFundAccounts(count int64, nameTemplate string, amount numeric.De
 
 	for i := int64(0); i < count; i++ {
 		waitGroup.Add(1)
-		go generateAndFundAccount(i, nameTemplate, fromShardID, toShardID, amount, nonce, accountsChannel, &waitGroup)
+		go generateAndFundAccountFrom(&config.Configuration.Funding.Account, i, nameTemplate, fromShardID, toShardID, amount, nonce, accountsChannel, &waitGroup)
 		nonce++
 	}
 
@@ -203,15 +189,15 @@ func GenerateAndFundAccounts(count int64, nameTemplate string, amount numeric.De
 	return accs, nil
 }
 
-func generateAndFundAccount(index int64, nameTemplate string, fromShardID uint32, toShardID uint32, amount numeric.Dec, nonce int, accountsChannel chan<- sdkAccounts.Account, waitGroup *sync.WaitGroup) {
+func generateAndFundAccountFrom(funder *sdkAccounts.Account, index int64, nameTemplate string, fromShardID uint32, toShardID uint32, amount numeric.Dec, nonce int, accountsChannel chan<- sdkAccounts.Account, waitGroup *sync.WaitGroup) {
 	defer waitGroup.Done()
 
 	accountName := fmt.Sprintf("%s%d", nameTemplate, index)
 	account, err := accounts.GenerateAccount(accountName)
 
 	if err == nil {
-		PerformFundingTransaction(
-			&config.Configuration.Funding.Account,
+		err = PerformFundingTransaction(
+			funder,
 			fromShardID,
 			account.Address,
 			toShardID,
@@ -222,6 +208,10 @@ func generateAndFundAccount(index int64, nameTemplate string, fromShardID uint32
 			config.Configuration.Funding.Timeout,
 			config.Configuration.Funding.Retry.Attempts,
 		)
+		if errors.Is(err, ErrBudgetExceeded) {
+			accounts.RemoveAccount(&account)
+			return
+		}
 		accountsChannel <- account
 	}
 }
@@ -276,6 +266,18 @@ func fundAccount(sourceAccount *sdkAccounts.Account, amount numeric.Dec, prefix
 // PerformFundingTransaction - performs a funding transaction including automatic retries
 func PerformFundingTransaction(account *sdkAccounts.Account, fromShardID uint32, toAddress string, toShardID uint32, amount numeric.Dec, nonce int, gasLimit int64, gasPrice numeric.Dec, timeout int, attempts int) error {
 	if amount.GT(numeric.NewDec(0)) {
+		// Only transfers from the funding account (or one of its sub-funders) to other accounts are tracked by the ledger
+		tracked := account.Address == config.Configuration.Funding.Account.Address && toAddress != config.Configuration.Funding.Account.Address
+		fannedOut := !tracked && Ledger.IsSubFunder(account.Address) && toAddress != config.Configuration.Funding.Account.Address
+		if tracked {
+			reservation, err := Ledger.Reserve(amount, gasLimit, gasPrice)
+			if err != nil {
+				logger.ErrorLog(fmt.Sprintf("Refusing to perform funding transaction from %s (shard: %d) to %s (shard: %d) of amount %f - error: %s", account.Address, fromShardID, toAddress, toShardID, amount, err.Error()), true)
+				return err
+			}
+			defer Ledger.Release(reservation)
+		}
+
 		for {
 			if attempts > 0 {
 				logger.FundingLog(fmt.Sprintf("Attempting funding transaction from %s (shard: %d) to %s (shard: %d) of amount %f!", account.Address, fromShardID, toAddress, toShardID, amount), config.Configuration.Funding.Verbose)
@@ -293,6 +295,11 @@ func PerformFundingTransaction(account *sdkAccounts.Account, fromShardID uint32,
 					success := sdkTransactions.IsTransactionSuccessful(rawTx)
 					if success {
 						logger.FundingLog(fmt.Sprintf("Successfully performed funding transaction (%s) from %s (shard: %d) to %s (shard: %d) of amount %f", rawTx["transactionHash"].(string), account.Address, fromShardID, toAddress, toShardID, amount), config.Configuration.Funding.Verbose)
+						if tracked {
+							Ledger.RecordFunding(account.Address, fromShardID, toAddress, toShardID, amount, rawTx, gasLimit, gasPrice)
+						} else if fannedOut {
+							Ledger.RecordFanOut(account.Address, fromShardID, toAddress, toShardID, amount, rawTx, gasLimit, gasPrice)
+						}
 						break
 					} else {
 						gasPrice = sdkTransactions.BumpGasPrice(gasPrice)
@@ -0,0 +1,68 @@
+package funding
+
+import (
+	"fmt"
+
+	sdkAccounts "github.com/harmony-one/go-lib/accounts"
+	sdkTxs "github.com/harmony-one/go-lib/transactions"
+	"github.com/harmony-one/harmony-tf/balances"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/transactions"
+)
+
+// ReturnFunds - returns any funds (minus a gas cost) held in any shard to the same shard of the receiver and verifies the final balances
+// Returns a failure per shard that couldn't be returned or still holds a returnable balance - the account must only be removed if there are none
+func ReturnFunds(account *sdkAccounts.Account, toAddress string) (failures []string) {
+	for shard := 0; shard < config.Configuration.Network.Shards; shard++ {
+		shardID := uint32(shard)
+		if err := returnFunds(account, shardID, toAddress, shardID); err != nil {
+			failures = append(failures, fmt.Sprintf("shard %d: %s", shardID, err.Error()))
+		}
+	}
+
+	return append(failures, verifyReturn(account)...)
+}
+
+func returnFunds(account *sdkAccounts.Account, fromShardID uint32, toAddress string, toShardID uint32) error {
+	amount, err := balances.GetShardBalance(account.Address, fromShardID)
+	if err != nil {
+		return err
+	}
+
+	if amount.IsNil() || !amount.GT(config.Configuration.Funding.Gas.Cost) {
+		return nil
+	}
+
+	amount = amount.Sub(config.Configuration.Funding.Gas.Cost)
+	rawTx, err := transactions.SendTransaction(account, fromShardID, toAddress, toShardID, amount, -1, config.Configuration.Funding.Gas.Limit, config.Configuration.Funding.Gas.Price, "", config.Configuration.Funding.Timeout)
+	if err != nil {
+		return err
+	}
+
+	if tx := sdkTxs.ToTransaction(account.Address, fromShardID, toAddress, toShardID, rawTx, err); !tx.Success {
+		return fmt.Errorf("the return transaction of %f wasn't successful", amount)
+	}
+
+	// Only successful returns are recorded, just like funding transfers
+	Ledger.RecordReturn(account.Address, fromShardID, toAddress, toShardID, amount, rawTx, config.Configuration.Funding.Gas.Limit, config.Configuration.Funding.Gas.Price)
+
+	return nil
+}
+
+// verifyReturn - checks that no shard still holds a returnable balance (i.e. more than the gas cost of a return transaction)
+func verifyReturn(account *sdkAccounts.Account) (failures []string) {
+	for shard := 0; shard < config.Configuration.Network.Shards; shard++ {
+		shardID := uint32(shard)
+		balance, err := balances.GetShardBalance(account.Address, shardID)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("shard %d: couldn't verify the final balance - %s", shardID, err.Error()))
+			continue
+		}
+
+		if !balance.IsNil() && balance.GT(config.Configuration.Funding.Gas.Cost) {
+			failures = append(failures, fmt.Sprintf("shard %d: %f is still left", shardID, balance))
+		}
+	}
+
+	return failures
+}
@@ -0,0 +1,177 @@
+package funding
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sdkAccounts "github.com/harmony-one/go-lib/accounts"
+	"github.com/harmony-one/harmony-tf/balances"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/metrics"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+// Source - represents a source of funds used to top up the funding account
+type Source interface {
+	// Name - the name of the funding source
+	Name() string
+	// TopUp - attempts to send at least the required amount to the funding account in the given shard
+	TopUp(shardID uint32, required numeric.Dec) error
+}
+
+// ConfiguredSources - sets up the funding sources configured using funding.sources
+func ConfiguredSources(accs []sdkAccounts.Account) (sources []Source, err error) {
+	for _, name := range config.Configuration.Funding.Sources {
+		switch strings.ToLower(name) {
+		case "local_keys":
+			sources = append(sources, &LocalKeysSource{Accounts: accs})
+		case "faucet":
+			if config.Configuration.Funding.Faucet.URL == "" {
+				return nil, fmt.Errorf("the faucet funding source requires funding.faucet.url to be set")
+			}
+			sources = append(sources, NewFaucetSource(config.Configuration.Funding.Faucet))
+		case "manual":
+			sources = append(sources, &ManualSource{Timeout: config.Configuration.Funding.Manual.Timeout})
+		default:
+			return nil, fmt.Errorf("invalid funding source %s - valid options: local_keys, faucet, manual", name)
+		}
+	}
+
+	return sources, nil
+}
+
+// TopUpFundingAccount - tops up the funding account in every funded shard to funding.target using the configured sources
+func TopUpFundingAccount(sources []Source) error {
+	shards, err := fundedShards()
+	if err != nil {
+		return err
+	}
+
+	for _, shardID := range shards {
+		for _, source := range sources {
+			balance, err := balances.GetShardBalance(config.Configuration.Funding.Account.Address, shardID)
+			if err != nil {
+				return err
+			}
+
+			if !InsufficientBalance(balance, config.Configuration.Funding.Target) {
+				break
+			}
+
+			required := config.Configuration.Funding.Target
+			if !balance.IsNil() {
+				required = required.Sub(balance)
+			}
+
+			logger.FundingLog(fmt.Sprintf("The funding account holds %f in shard %d - requesting %f from funding source %s", balance, shardID, required, source.Name()), true)
+			if err := source.TopUp(shardID, required); err != nil {
+				logger.ErrorLog(fmt.Sprintf("Funding source %s failed to top up the funding account in shard %d - error: %s", source.Name(), shardID, err.Error()), true)
+			}
+		}
+	}
+
+	return nil
+}
+
+func fundedShards() (shards []uint32, err error) {
+	if config.Configuration.Funding.Shards == "all" {
+		for shard := 0; shard < config.Configuration.Network.Shards; shard++ {
+			shards = append(shards, uint32(shard))
+		}
+		return shards, nil
+	}
+
+	shard, err := strconv.ParseUint(config.Configuration.Funding.Shards, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	return []uint32{uint32(shard)}, nil
+}
+
+// LocalKeysSource - funds the funding account by draining the keys found in keys/<network>/
+type LocalKeysSource struct {
+	Accounts []sdkAccounts.Account
+}
+
+// Name - the name of the funding source
+func (source *LocalKeysSource) Name() string {
+	return "local_keys"
+}
+
+// TopUp - sends the balances (minus gas) of all local keys in the given shard to the funding account
+func (source *LocalKeysSource) TopUp(shardID uint32, required numeric.Dec) error {
+	if len(source.Accounts) == 0 {
+		return fmt.Errorf("there are no funded keys in keys/%s", config.Configuration.Network.Name)
+	}
+
+	var waitGroup sync.WaitGroup
+	for _, account := range source.Accounts {
+		FundFundingAccountInShard(account, shardID, &waitGroup)
+	}
+	waitGroup.Wait()
+
+	return nil
+}
+
+// ManualSource - waits for the funding account to be topped up externally, e.g. by a person or another system
+type ManualSource struct {
+	Timeout int
+}
+
+// Name - the name of the funding source
+func (source *ManualSource) Name() string {
+	return "manual"
+}
+
+// TopUp - waits until the funding account has received the required amount in the given shard
+func (source *ManualSource) TopUp(shardID uint32, required numeric.Dec) error {
+	baseline, err := fundingAccountBalance(shardID)
+	if err != nil {
+		return err
+	}
+
+	logger.WarningLog(fmt.Sprintf("Please send at least %f to the funding account %s in shard %d - waiting up to %d seconds for the funds to arrive", required, config.Configuration.Funding.Account.Address, shardID, source.Timeout), true)
+
+	return waitForTopUp(shardID, baseline, required, time.Duration(source.Timeout)*time.Second)
+}
+
+// waitForTopUp - polls the balance of the funding account until it has increased by the required amount compared to the baseline or the timeout has passed
+// The baseline has to be retrieved before the funds were requested - otherwise funds arriving in the meantime would never count as received
+func waitForTopUp(shardID uint32, baseline numeric.Dec, required numeric.Dec, timeout time.Duration) error {
+	wait := time.Duration(config.Configuration.Network.Balances.Retry.Wait) * time.Second
+	if wait <= 0 {
+		wait = time.Second
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		balance, err := fundingAccountBalance(shardID)
+		if err == nil && balance.Sub(baseline).GTE(required) {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the funding account %s didn't receive %f in shard %d within %v", config.Configuration.Funding.Account.Address, required, shardID, timeout)
+		}
+
+		metrics.Sleep("funding top up wait", wait)
+	}
+}
+
+// fundingAccountBalance - the balance of the funding account in a given shard - a missing balance counts as 0
+func fundingAccountBalance(shardID uint32) (numeric.Dec, error) {
+	balance, err := balances.GetShardBalance(config.Configuration.Funding.Account.Address, shardID)
+	if err != nil {
+		return numeric.NewDec(0), err
+	}
+	if balance.IsNil() {
+		balance = numeric.NewDec(0)
+	}
+
+	return balance, nil
+}
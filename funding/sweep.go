@@ -0,0 +1,273 @@
+package funding
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdkAccounts "github.com/harmony-one/go-lib/accounts"
+	sdkDelegation "github.com/harmony-one/go-lib/staking/delegation"
+	sdkTxs "github.com/harmony-one/go-lib/transactions"
+	"github.com/harmony-one/go-sdk/pkg/store"
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/balances"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/utils"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+// SweepSummary - summarizes the result of sweeping orphaned test accounts
+type SweepSummary struct {
+	DryRun    bool
+	Accounts  int
+	Recovered map[uint32]numeric.Dec
+	Removed   []string
+	Remaining []string
+	Skipped   []string
+}
+
+// Total - the total recovered amount across all shards
+func (summary *SweepSummary) Total() numeric.Dec {
+	total := numeric.NewDec(0)
+	for _, amount := range summary.Recovered {
+		total = total.Add(amount)
+	}
+
+	return total
+}
+
+// String - formats the sweep summary
+func (summary *SweepSummary) String() string {
+	var builder strings.Builder
+
+	action := "Recovered"
+	if summary.DryRun {
+		action = "Would recover"
+	}
+
+	builder.WriteString(fmt.Sprintf("Found a total of %d orphaned test account(s)\n", summary.Accounts))
+
+	shards := []int{}
+	for shardID := range summary.Recovered {
+		shards = append(shards, int(shardID))
+	}
+	sort.Ints(shards)
+	for _, shardID := range shards {
+		builder.WriteString(fmt.Sprintf("%s %f in shard %d\n", action, summary.Recovered[uint32(shardID)], shardID))
+	}
+	builder.WriteString(fmt.Sprintf("%s a total of %f\n", action, summary.Total()))
+
+	if !summary.DryRun {
+		builder.WriteString(fmt.Sprintf("Removed %d account(s) from the keystore\n", len(summary.Removed)))
+		if len(summary.Remaining) > 0 {
+			builder.WriteString(fmt.Sprintf("Kept %d account(s) that still hold funds: %s\n", len(summary.Remaining), strings.Join(summary.Remaining, ", ")))
+		}
+	}
+
+	if len(summary.Skipped) > 0 {
+		builder.WriteString(fmt.Sprintf("Skipped %d account(s) that still have delegations or pending undelegations: %s\n", len(summary.Skipped), strings.Join(summary.Skipped, ", ")))
+	}
+
+	return builder.String()
+}
+
+// LookupFundingAccount - resolves the address of the funding account without generating or funding it
+func LookupFundingAccount() error {
+	if config.Configuration.Funding.Account.Address == "" {
+		resolvedAddress := sdkAccounts.FindAccountAddressByName(config.Configuration.Funding.Account.Name)
+		if resolvedAddress == "" {
+			return fmt.Errorf("couldn't find the funding account %s in the keystore - please specify its address using --address", config.Configuration.Funding.Account.Name)
+		}
+		config.Configuration.Funding.Account.Address = resolvedAddress
+	}
+
+	return nil
+}
+
+// OrphanedTestAccounts - lists all keystore accounts generated by test cases for the current network, excluding the given account names
+func OrphanedTestAccounts(excludedNames []string) (accs []sdkAccounts.Account) {
+	prefix := accounts.GenerateAccountName("TestCase_")
+
+	for _, name := range store.LocalAccounts() {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		if !utils.StringSliceContains(excludedNames, name) {
+			if account := sdkAccounts.FindAccountByName(name); account.Address != "" {
+				account.Passphrase = config.Configuration.Account.Passphrase
+				accs = append(accs, account)
+			}
+		}
+	}
+
+	return accs
+}
+
+// Sweep - returns all funds (except for gas) held by orphaned test accounts to the funding account and removes the accounts from the keystore once they're empty
+func Sweep(excludedNames []string, dryRun bool) (summary SweepSummary, err error) {
+	return SweepAccounts(OrphanedTestAccounts(excludedNames), dryRun)
+}
+
+// SweepAccounts - returns all funds (except for gas) held by the given accounts to the funding account and removes the accounts once they're empty
+func SweepAccounts(orphaned []sdkAccounts.Account, dryRun bool) (summary SweepSummary, err error) {
+	if err := LookupFundingAccount(); err != nil {
+		return summary, err
+	}
+
+	toShardID, sameShard, err := sweepTargetShard()
+	if err != nil {
+		return summary, err
+	}
+
+	gasPrice := config.Configuration.Funding.Gas.Price
+	gasLimit := int64(sdkTxs.TxGas)
+
+	summary = SweepSummary{
+		DryRun:    dryRun,
+		Accounts:  len(orphaned),
+		Recovered: make(map[uint32]numeric.Dec),
+	}
+
+	for i := range orphaned {
+		account := &orphaned[i]
+
+		if hasDelegations(account.Address) {
+			logger.WarningLog(fmt.Sprintf("Account %s, address: %s still has delegations or pending undelegations - skipping it", account.Name, account.Address), true)
+			summary.Skipped = append(summary.Skipped, account.Address)
+			continue
+		}
+
+		for shard := 0; shard < config.Configuration.Network.Shards; shard++ {
+			fromShardID := uint32(shard)
+			targetShardID := toShardID
+			if sameShard {
+				targetShardID = fromShardID
+			}
+
+			if dryRun {
+				balance, err := balances.GetShardBalance(account.Address, fromShardID)
+				if err == nil && !balance.IsNil() && balance.GT(sweepGasCost(gasLimit, gasPrice)) {
+					amount := balance.Sub(sweepGasCost(gasLimit, gasPrice))
+					logger.FundingLog(fmt.Sprintf("Account %s, address: %s holds %f in shard %d - would return %f to the funding account in shard %d", account.Name, account.Address, balance, fromShardID, amount, targetShardID), true)
+					addRecovered(summary.Recovered, fromShardID, amount)
+				}
+				continue
+			}
+
+			amount, err := sweepShard(account, fromShardID, targetShardID, gasLimit, gasPrice)
+			if err != nil {
+				logger.ErrorLog(fmt.Sprintf("Failed to return the funds of account %s, address: %s in shard %d - error: %s", account.Name, account.Address, fromShardID, err.Error()), true)
+			}
+			if amount.IsPositive() {
+				addRecovered(summary.Recovered, fromShardID, amount)
+			}
+		}
+
+		if dryRun {
+			continue
+		}
+
+		if isEmpty(account.Address) {
+			accounts.RemoveAccount(account)
+			summary.Removed = append(summary.Removed, account.Address)
+		} else {
+			summary.Remaining = append(summary.Remaining, account.Address)
+		}
+	}
+
+	return summary, nil
+}
+
+// sweepShard - returns the balance of an account in a given shard to the funding account
+// The amount is recomputed for every attempt since a bumped gas price changes how much can be sent - the shard counts as swept once the balance no longer covers the gas cost
+func sweepShard(account *sdkAccounts.Account, fromShardID uint32, toShardID uint32, gasLimit int64, gasPrice numeric.Dec) (recovered numeric.Dec, err error) {
+	recovered = numeric.NewDec(0)
+
+	for attempt := 0; attempt < config.Configuration.Funding.Retry.Attempts; attempt++ {
+		balance, err := balances.GetShardBalance(account.Address, fromShardID)
+		if err != nil {
+			return recovered, err
+		}
+
+		gasCost := sweepGasCost(gasLimit, gasPrice)
+		if balance.IsNil() || !balance.GT(gasCost) {
+			return recovered, nil
+		}
+
+		amount := balance.Sub(gasCost)
+		logger.FundingLog(fmt.Sprintf("Account %s, address: %s holds %f in shard %d - returning %f to the funding account in shard %d", account.Name, account.Address, balance, fromShardID, amount, toShardID), true)
+
+		if err := PerformFundingTransaction(account, fromShardID, config.Configuration.Funding.Account.Address, toShardID, amount, -1, gasLimit, gasPrice, config.Configuration.Funding.Timeout, 1); err != nil {
+			return recovered, err
+		}
+
+		remaining, err := balances.GetShardBalance(account.Address, fromShardID)
+		if err == nil && !remaining.IsNil() && remaining.LT(balance) {
+			recovered = recovered.Add(amount)
+			continue
+		}
+
+		gasPrice = sdkTxs.BumpGasPrice(gasPrice)
+	}
+
+	return recovered, fmt.Errorf("the balance still covers the gas cost after %d attempt(s)", config.Configuration.Funding.Retry.Attempts)
+}
+
+// sweepGasCost - the gas cost of a sweep transfer - gas prices are specified in nano denominations
+func sweepGasCost(gasLimit int64, gasPrice numeric.Dec) numeric.Dec {
+	return numeric.NewDec(gasLimit).Mul(gasPrice).Quo(numeric.NewDec(1000000000))
+}
+
+func sweepTargetShard() (shardID uint32, sameShard bool, err error) {
+	if config.Configuration.Funding.Shards == "all" || config.Configuration.Funding.Shards == "" {
+		return 0, true, nil
+	}
+
+	shard, err := strconv.ParseUint(config.Configuration.Funding.Shards, 10, 32)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return uint32(shard), false, nil
+}
+
+func hasDelegations(address string) bool {
+	delegations, err := sdkDelegation.ByDelegator(config.Configuration.Network.API.NodeAddress(0), address)
+	if err != nil {
+		// Play it safe - if we can't check the delegations we shouldn't remove the account
+		return true
+	}
+
+	for _, delegation := range delegations {
+		if (!delegation.Amount.IsNil() && delegation.Amount.IsPositive()) || len(delegation.Undelegations) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isEmpty - whether or not an account holds nothing but dust in every shard, i.e. nothing that could pay for its own transfer
+func isEmpty(address string) bool {
+	dust := sweepGasCost(int64(sdkTxs.TxGas), config.Configuration.Funding.Gas.Price)
+
+	for shard := 0; shard < config.Configuration.Network.Shards; shard++ {
+		balance, err := balances.GetShardBalance(address, uint32(shard))
+		if err != nil || balance.IsNil() || balance.GT(dust) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func addRecovered(recovered map[uint32]numeric.Dec, shardID uint32, amount numeric.Dec) {
+	if current, ok := recovered[shardID]; ok {
+		recovered[shardID] = current.Add(amount)
+	} else {
+		recovered[shardID] = amount
+	}
+}
@@ -6,11 +6,15 @@ require (
 	github.com/elliotchance/orderedmap v1.2.1
 	github.com/ethereum/go-ethereum v1.8.27
 	github.com/gookit/color v1.2.4
+	github.com/gorilla/websocket v1.4.2
+	github.com/harmony-one/bls v0.0.7-0.20191214005344-88c23f91a8a9
 	github.com/harmony-one/go-lib v0.0.0-20200722200701-595af2005711
 	github.com/harmony-one/go-sdk v1.2.1-0.20200708192334-a30c33c1d9c1
 	github.com/harmony-one/harmony v1.9.1-0.20200722170829-a354b93676e9
 	github.com/mackerelio/go-osstat v0.1.0
+	github.com/mitchellh/go-homedir v1.1.0
 	github.com/pkg/errors v0.9.1
 	github.com/spf13/cobra v1.0.0
+	github.com/tyler-smith/go-bip39 v1.0.2
 	gopkg.in/yaml.v2 v2.2.8
 )
@@ -39,12 +39,21 @@ func LoadKeys() (allAccounts []sdkAccounts.Account, err error) {
 	return allAccounts, nil
 }
 
+// KeysPath - the path to the keys directory of the current network
+func KeysPath() string {
+	return filepath.Join(config.Configuration.Framework.BasePath, "keys", config.Configuration.Network.Name)
+}
+
+// PrivateKeysPath - the path to the private keys file of the current network
+func PrivateKeysPath() string {
+	return filepath.Join(KeysPath(), "private_keys.txt")
+}
+
 // LoadPrivateKeys - loads the source accounts using a .txt file including new line separated private keys
 func LoadPrivateKeys() (accs []sdkAccounts.Account, err error) {
 	unfilteredAccounts := []sdkAccounts.Account{}
 
-	path := filepath.Join(config.Configuration.Framework.BasePath, "keys", config.Configuration.Network.Name, "private_keys.txt")
-	privateKeys, err := utils.FileToLines(path)
+	privateKeys, err := utils.FileToLines(PrivateKeysPath())
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +88,7 @@ func LoadKeystoreKeys() (accs []sdkAccounts.Account, err error) {
 	KeyMapping = make(map[string]string)
 	unfilteredAccounts := []sdkAccounts.Account{}
 
-	path := filepath.Join(config.Configuration.Framework.BasePath, "keys", config.Configuration.Network.Name)
+	path := KeysPath()
 	if err := IdentifyKeystoreKeys(path); err != nil {
 		return nil, err
 	}
@@ -120,7 +129,17 @@ func FilterKeys(unfilteredAccounts []sdkAccounts.Account) (accounts []sdkAccount
 
 			if len(KeyMapping) > 0 {
 				if sourcePath, ok := KeyMapping[account.Address]; ok {
-					os.RemoveAll(sourcePath)
+					quarantineLowBalanceKey(account.Address, sourcePath)
+				}
+			}
+		}
+
+		for _, account := range hasFunds {
+			if len(KeyMapping) > 0 {
+				if _, ok := KeyMapping[account.Address]; ok {
+					if totalBalance, err := config.Configuration.Network.API.GetTotalBalance(account.Address); err == nil {
+						ObserveBalance(account.Address, totalBalance, false)
+					}
 				}
 			}
 		}
@@ -133,10 +152,31 @@ func FilterKeys(unfilteredAccounts []sdkAccounts.Account) (accounts []sdkAccount
 	return accounts, nil
 }
 
+// quarantineLowBalanceKey - source keystore files are only quarantined after account.quarantine_after consecutive low balance observations
+func quarantineLowBalanceKey(address string, sourcePath string) {
+	totalBalance, err := config.Configuration.Network.API.GetTotalBalance(address)
+	if err != nil {
+		// Never act on a balance we couldn't retrieve
+		return
+	}
+
+	count, err := ObserveBalance(address, totalBalance, true)
+	if err != nil || count < config.Configuration.Account.QuarantineAfter {
+		return
+	}
+
+	if quarantinedPath, err := Quarantine(address, sourcePath, totalBalance); err == nil {
+		fmt.Println(fmt.Sprintf("Key %s has held less than %f for %d consecutive runs - moved %s to %s (use keys restore to bring it back)", address, config.Configuration.Funding.MinimumFunds, count, sourcePath, quarantinedPath))
+	}
+}
+
 // IdentifyKeystoreKeys - identifies the key store files in a given path - also supports an unlimited amount of subdirectories
 func IdentifyKeystoreKeys(path string) error {
 	var files []string
 	err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+		if info != nil && info.IsDir() && info.Name() == quarantineDirectory {
+			return filepath.SkipDir
+		}
 		files = append(files, path)
 		return nil
 	})
@@ -180,8 +220,15 @@ func parseKeystoreJSON(data string) (map[string]interface{}, error) {
 		return nil, err
 	}
 
-	jsonData := rawData.(map[string]interface{})
-	ethAddress := jsonData["address"].(string)
+	jsonData, ok := rawData.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("keystore data isn't a JSON object")
+	}
+
+	ethAddress, ok := jsonData["address"].(string)
+	if !ok {
+		return nil, fmt.Errorf("keystore data doesn't contain an address")
+	}
 	bech32Address := address.ToBech32(address.Parse(ethAddress))
 
 	if bech32Address != "" {
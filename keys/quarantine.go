@@ -0,0 +1,242 @@
+package keys
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/utils"
+	"github.com/harmony-one/harmony/numeric"
+	"gopkg.in/yaml.v2"
+)
+
+const quarantineDirectory = ".quarantine"
+
+var observationsMutex sync.Mutex
+
+// KeyObservations - keeps track of consecutive low balance observations per source key address
+type KeyObservations struct {
+	Network   string                    `yaml:"network"`
+	Addresses map[string]KeyObservation `yaml:"addresses"`
+}
+
+// KeyObservation - represents the low balance observations for a given source key
+type KeyObservation struct {
+	LowBalanceCount int       `yaml:"low_balance_count"`
+	LastBalance     string    `yaml:"last_balance"`
+	LastObservedAt  time.Time `yaml:"last_observed_at"`
+}
+
+// QuarantineEntry - represents an entry in the quarantine audit log
+type QuarantineEntry struct {
+	Action  string    `json:"action"`
+	Address string    `json:"address"`
+	Balance string    `json:"balance,omitempty"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+	Time    time.Time `json:"time"`
+}
+
+// QuarantinePath - the path to the quarantine directory of the current network
+func QuarantinePath() string {
+	return filepath.Join(KeysPath(), quarantineDirectory)
+}
+
+// QuarantineAuditLogPath - the path to the quarantine audit log of the current network
+func QuarantineAuditLogPath() string {
+	return filepath.Join(QuarantinePath(), "audit.log")
+}
+
+// KeyObservationsPath - the path to the low balance observations of the current network
+func KeyObservationsPath() string {
+	return filepath.Join(config.Configuration.StatePath(), "key_observations.yml")
+}
+
+// LoadKeyObservations - loads the low balance observations for the current network
+func LoadKeyObservations() (observations KeyObservations, err error) {
+	if err = utils.ParseYaml(KeyObservationsPath(), &observations); err != nil {
+		return KeyObservations{}, err
+	}
+
+	if observations.Network == "" {
+		observations.Network = config.Configuration.Network.Name
+	}
+
+	if observations.Addresses == nil {
+		observations.Addresses = make(map[string]KeyObservation)
+	}
+
+	return observations, nil
+}
+
+// Save - persists the low balance observations to disk
+func (observations *KeyObservations) Save() error {
+	path := KeyObservationsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(observations)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// ObserveBalance - records a balance observation for a source key and returns the number of consecutive low balance observations
+func ObserveBalance(address string, balance numeric.Dec, low bool) (int, error) {
+	observationsMutex.Lock()
+	defer observationsMutex.Unlock()
+
+	observations, err := LoadKeyObservations()
+	if err != nil {
+		return 0, err
+	}
+
+	observation := observations.Addresses[address]
+	if low {
+		observation.LowBalanceCount++
+	} else {
+		observation.LowBalanceCount = 0
+	}
+	observation.LastBalance = fmt.Sprintf("%f", balance)
+	observation.LastObservedAt = time.Now().UTC()
+
+	if observation.LowBalanceCount > 0 {
+		observations.Addresses[address] = observation
+	} else {
+		delete(observations.Addresses, address)
+	}
+
+	if err := observations.Save(); err != nil {
+		return 0, err
+	}
+
+	return observation.LowBalanceCount, nil
+}
+
+// Quarantine - moves a source keystore file to the quarantine directory and adds an entry to the audit log
+func Quarantine(address string, sourcePath string, balance numeric.Dec) (string, error) {
+	if err := os.MkdirAll(QuarantinePath(), 0700); err != nil {
+		return "", err
+	}
+
+	targetPath := availablePath(filepath.Join(QuarantinePath(), filepath.Base(sourcePath)))
+	if err := os.Rename(sourcePath, targetPath); err != nil {
+		return "", err
+	}
+
+	err := appendAuditLogEntry(QuarantineEntry{
+		Action:  "quarantined",
+		Address: address,
+		Balance: fmt.Sprintf("%f", balance),
+		From:    sourcePath,
+		To:      targetPath,
+		Time:    time.Now().UTC(),
+	})
+
+	return targetPath, err
+}
+
+// QuarantinedKeys - identifies all keystore files in the quarantine directory - returns a map of address => path
+func QuarantinedKeys() (map[string]string, error) {
+	quarantined := make(map[string]string)
+
+	files, err := ioutil.ReadDir(QuarantinePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return quarantined, nil
+		}
+		return nil, err
+	}
+
+	for _, file := range files {
+		// The audit log lives next to the quarantined keys but must never be restored as one
+		if file.IsDir() || file.Name() == filepath.Base(QuarantineAuditLogPath()) {
+			continue
+		}
+
+		path := filepath.Join(QuarantinePath(), file.Name())
+		keyData, err := utils.ReadFileToString(path)
+		if err != nil {
+			continue
+		}
+
+		keyDetails, err := parseKeystoreJSON(keyData)
+		if err != nil || !isKeystore(keyDetails) {
+			continue
+		}
+
+		if address, ok := keyDetails["address"].(string); ok && address != "" {
+			quarantined[address] = path
+		}
+	}
+
+	return quarantined, nil
+}
+
+// Restore - moves a quarantined keystore file back to keys/<network>/, resets its low balance observations and adds an entry to the audit log
+func Restore(address string, quarantinedPath string) (string, error) {
+	targetPath := availablePath(filepath.Join(KeysPath(), filepath.Base(quarantinedPath)))
+	if err := os.Rename(quarantinedPath, targetPath); err != nil {
+		return "", err
+	}
+
+	if _, err := ObserveBalance(address, numeric.NewDec(0), false); err != nil {
+		return targetPath, err
+	}
+
+	err := appendAuditLogEntry(QuarantineEntry{
+		Action:  "restored",
+		Address: address,
+		From:    quarantinedPath,
+		To:      targetPath,
+		Time:    time.Now().UTC(),
+	})
+
+	return targetPath, err
+}
+
+// isKeystore - whether or not parsed JSON data has the fields of a keystore file rather than e.g. a single audit log entry
+func isKeystore(keyDetails map[string]interface{}) bool {
+	_, hasVersion := keyDetails["version"]
+	_, hasCrypto := keyDetails["crypto"]
+	if !hasCrypto {
+		// Older keystore files use a capitalized crypto field
+		_, hasCrypto = keyDetails["Crypto"]
+	}
+
+	return hasVersion && hasCrypto
+}
+
+func appendAuditLogEntry(entry QuarantineEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(QuarantineAuditLogPath(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+
+	return err
+}
+
+// availablePath - appends a timestamp to the path if a file already exists at the given path
+func availablePath(path string) string {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return path
+	}
+
+	return fmt.Sprintf("%s.%d", path, time.Now().UTC().UnixNano())
+}
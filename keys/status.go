@@ -0,0 +1,239 @@
+package keys
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/harmony-one/harmony-tf/balances"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/utils"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+// KeyStatus - represents the balances of a key found in keys/<network>/
+type KeyStatus struct {
+	Address  string
+	Source   string
+	Path     string
+	Balances map[uint32]numeric.Dec
+	Total    numeric.Dec
+	Usable   bool
+	Error    error
+}
+
+// Inventory - represents the state of all keys found in keys/<network>/ as well as the funding account
+type Inventory struct {
+	Network        string
+	MinimumFunds   numeric.Dec
+	Keys           []KeyStatus
+	FundingAccount *KeyStatus
+}
+
+// Status - identifies all private keys and keystore files in keys/<network>/ and retrieves their balances - nothing is imported into or removed from the keystore
+func Status(fundingAddress string) (inventory Inventory, err error) {
+	inventory = Inventory{
+		Network:      config.Configuration.Network.Name,
+		MinimumFunds: config.Configuration.Funding.MinimumFunds,
+	}
+
+	path := PrivateKeysPath()
+	privateKeys, err := utils.FileToLines(path)
+	if err != nil {
+		return inventory, err
+	}
+
+	for _, privateKey := range privateKeys {
+		address, err := PrivateKeyToAddress(privateKey)
+		if err == nil {
+			inventory.Keys = append(inventory.Keys, keyStatus(address, "private_key", path))
+		}
+	}
+
+	KeyMapping = make(map[string]string)
+	if err := IdentifyKeystoreKeys(KeysPath()); err != nil {
+		return inventory, err
+	}
+
+	for address, keyPath := range KeyMapping {
+		inventory.Keys = append(inventory.Keys, keyStatus(address, "keystore", keyPath))
+	}
+
+	sort.SliceStable(inventory.Keys, func(i, j int) bool {
+		return inventory.Keys[i].Address < inventory.Keys[j].Address
+	})
+
+	if fundingAddress != "" {
+		fundingStatus := keyStatus(fundingAddress, "funding_account", "")
+		fundingStatus.Usable = fundingStatus.Error == nil
+		inventory.FundingAccount = &fundingStatus
+	}
+
+	return inventory, nil
+}
+
+func keyStatus(address string, source string, path string) KeyStatus {
+	status := KeyStatus{
+		Address:  address,
+		Source:   source,
+		Path:     path,
+		Balances: make(map[uint32]numeric.Dec),
+		Total:    numeric.NewDec(0),
+	}
+
+	for shard := 0; shard < config.Configuration.Network.Shards; shard++ {
+		shardID := uint32(shard)
+		balance, err := balances.GetShardBalance(address, shardID)
+		if err != nil {
+			status.Error = err
+			continue
+		}
+
+		if balance.IsNil() {
+			balance = numeric.NewDec(0)
+		}
+
+		status.Balances[shardID] = balance
+		status.Total = status.Total.Add(balance)
+	}
+
+	// Same criteria as FilterKeys - keys at or below the minimum funds are ignored when funding the funding account
+	status.Usable = status.Error == nil && status.Total.GT(config.Configuration.Funding.MinimumFunds)
+
+	return status
+}
+
+// TotalUsable - the total funds held by usable keys and the funding account
+func (inventory *Inventory) TotalUsable() numeric.Dec {
+	total := numeric.NewDec(0)
+
+	for _, key := range inventory.Keys {
+		if key.Usable {
+			total = total.Add(key.Total)
+		}
+	}
+
+	if inventory.FundingAccount != nil && inventory.FundingAccount.Usable {
+		total = total.Add(inventory.FundingAccount.Total)
+	}
+
+	return total
+}
+
+// Table - formats the inventory as a table
+func (inventory *Inventory) Table() string {
+	var builder strings.Builder
+
+	header := []string{"Address", "Source"}
+	for shard := 0; shard < config.Configuration.Network.Shards; shard++ {
+		header = append(header, fmt.Sprintf("Shard %d", shard))
+	}
+	header = append(header, "Total", "Usable")
+
+	format := "%-45s %-16s" + strings.Repeat(" %-20s", len(header)-2) + "\n"
+	builder.WriteString(fmt.Sprintf(format, toInterfaces(header)...))
+	builder.WriteString(fmt.Sprintf("%s\n", strings.Repeat("-", 50)))
+
+	for _, key := range inventory.Keys {
+		builder.WriteString(fmt.Sprintf(format, toInterfaces(key.row())...))
+	}
+
+	if inventory.FundingAccount != nil {
+		builder.WriteString(fmt.Sprintf("%s\n", strings.Repeat("-", 50)))
+		builder.WriteString(fmt.Sprintf(format, toInterfaces(inventory.FundingAccount.row())...))
+	}
+
+	builder.WriteString(fmt.Sprintf("%s\n", strings.Repeat("-", 50)))
+	builder.WriteString(fmt.Sprintf("Found a total of %d key(s) in %s\n", len(inventory.Keys), KeysPath()))
+	builder.WriteString(fmt.Sprintf("Total usable funds (keys holding more than %f + the funding account): %f\n", inventory.MinimumFunds, inventory.TotalUsable()))
+
+	for _, key := range inventory.Keys {
+		if key.Error != nil {
+			builder.WriteString(fmt.Sprintf("Failed to retrieve all balances for %s - error: %s\n", key.Address, key.Error.Error()))
+		}
+	}
+
+	return builder.String()
+}
+
+func (status *KeyStatus) row() []string {
+	row := []string{status.Address, status.Source}
+	for shard := 0; shard < config.Configuration.Network.Shards; shard++ {
+		if balance, ok := status.Balances[uint32(shard)]; ok {
+			row = append(row, fmt.Sprintf("%f", balance))
+		} else {
+			row = append(row, "-")
+		}
+	}
+
+	return append(row, fmt.Sprintf("%f", status.Total), fmt.Sprintf("%t", status.Usable))
+}
+
+type jsonKeyStatus struct {
+	Address  string            `json:"address"`
+	Source   string            `json:"source"`
+	Path     string            `json:"path,omitempty"`
+	Balances map[string]string `json:"balances"`
+	Total    string            `json:"total"`
+	Usable   bool              `json:"usable"`
+	Error    string            `json:"error,omitempty"`
+}
+
+type jsonInventory struct {
+	Network        string          `json:"network"`
+	MinimumFunds   string          `json:"minimum_funds"`
+	Keys           []jsonKeyStatus `json:"keys"`
+	FundingAccount *jsonKeyStatus  `json:"funding_account,omitempty"`
+	TotalUsable    string          `json:"total_usable"`
+}
+
+// JSON - formats the inventory as JSON
+func (inventory *Inventory) JSON() ([]byte, error) {
+	output := jsonInventory{
+		Network:      inventory.Network,
+		MinimumFunds: fmt.Sprintf("%f", inventory.MinimumFunds),
+		Keys:         []jsonKeyStatus{},
+		TotalUsable:  fmt.Sprintf("%f", inventory.TotalUsable()),
+	}
+
+	for _, key := range inventory.Keys {
+		output.Keys = append(output.Keys, key.toJSON())
+	}
+
+	if inventory.FundingAccount != nil {
+		fundingAccount := inventory.FundingAccount.toJSON()
+		output.FundingAccount = &fundingAccount
+	}
+
+	return json.MarshalIndent(output, "", "  ")
+}
+
+func (status *KeyStatus) toJSON() jsonKeyStatus {
+	output := jsonKeyStatus{
+		Address:  status.Address,
+		Source:   status.Source,
+		Path:     status.Path,
+		Balances: make(map[string]string),
+		Total:    fmt.Sprintf("%f", status.Total),
+		Usable:   status.Usable,
+	}
+
+	for shardID, balance := range status.Balances {
+		output.Balances[fmt.Sprintf("%d", shardID)] = fmt.Sprintf("%f", balance)
+	}
+
+	if status.Error != nil {
+		output.Error = status.Error.Error()
+	}
+
+	return output
+}
+
+func toInterfaces(values []string) (interfaces []interface{}) {
+	for _, value := range values {
+		interfaces = append(interfaces, value)
+	}
+
+	return interfaces
+}
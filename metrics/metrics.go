@@ -0,0 +1,296 @@
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// OutcomeOK - the call succeeded
+	OutcomeOK = "ok"
+
+	// OutcomeRPCError - the endpoint answered with a JSON-RPC error
+	OutcomeRPCError = "rpc error"
+
+	// OutcomeThrottled - the endpoint throttled the call
+	OutcomeThrottled = "throttled"
+
+	// OutcomeTransportError - the call failed before a response was received (connection errors, timeouts etc.)
+	OutcomeTransportError = "transport error"
+
+	// OutcomeReplayed - the call was answered from a cassette
+	OutcomeReplayed = "replayed"
+)
+
+var (
+	// RPC - metrics for all JSON-RPC calls
+	RPC = &RPCMetrics{calls: make(map[callKey]*CallStats)}
+
+	// Sleeps - time spent sleeping in the framework itself (wait times, retry waits, backoff)
+	Sleeps = &SleepMetrics{reasons: make(map[string]*SleepStats)}
+
+	// Buckets - the upper bounds of the latency histogram buckets - the last bucket holds all slower calls
+	Buckets = []time.Duration{
+		10 * time.Millisecond,
+		25 * time.Millisecond,
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+		250 * time.Millisecond,
+		500 * time.Millisecond,
+		time.Second,
+		2500 * time.Millisecond,
+		5 * time.Second,
+		10 * time.Second,
+	}
+)
+
+type callKey struct {
+	method   string
+	endpoint string
+	outcome  string
+}
+
+// RPCMetrics - JSON-RPC call counts and latencies per method, endpoint and outcome
+type RPCMetrics struct {
+	mutex sync.Mutex
+	calls map[callKey]*CallStats
+}
+
+// CallStats - the counts and latencies of JSON-RPC calls with the same method, endpoint and outcome
+type CallStats struct {
+	Method    string
+	Endpoint  string
+	Outcome   string
+	Count     int
+	Total     time.Duration
+	Min       time.Duration
+	Max       time.Duration
+	Histogram []int // Call counts per bucket in Buckets + one bucket for calls slower than the last bucket
+}
+
+// Observe - records a JSON-RPC call
+func (metrics *RPCMetrics) Observe(method string, endpoint string, outcome string, latency time.Duration) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	key := callKey{method: method, endpoint: endpoint, outcome: outcome}
+	stats, ok := metrics.calls[key]
+	if !ok {
+		stats = &CallStats{Method: method, Endpoint: endpoint, Outcome: outcome, Min: latency, Histogram: make([]int, len(Buckets)+1)}
+		metrics.calls[key] = stats
+	}
+
+	stats.Count++
+	stats.Total += latency
+	if latency < stats.Min {
+		stats.Min = latency
+	}
+	if latency > stats.Max {
+		stats.Max = latency
+	}
+	stats.Histogram[bucket(latency)]++
+}
+
+// Stats - all recorded call stats sorted by method, endpoint and outcome
+func (metrics *RPCMetrics) Stats() (stats []CallStats) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	for _, callStats := range metrics.calls {
+		copied := *callStats
+		copied.Histogram = append([]int{}, callStats.Histogram...)
+		stats = append(stats, copied)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Method != stats[j].Method {
+			return stats[i].Method < stats[j].Method
+		}
+		if stats[i].Endpoint != stats[j].Endpoint {
+			return stats[i].Endpoint < stats[j].Endpoint
+		}
+		return stats[i].Outcome < stats[j].Outcome
+	})
+
+	return stats
+}
+
+// Average - the average latency
+func (stats CallStats) Average() time.Duration {
+	if stats.Count == 0 {
+		return 0
+	}
+
+	return stats.Total / time.Duration(stats.Count)
+}
+
+// Percentile - an estimate of a given latency percentile (0-100) using the upper bounds of the histogram buckets
+func (stats CallStats) Percentile(percentile float64) time.Duration {
+	target := int(float64(stats.Count)*percentile/100 + 0.5)
+	if target < 1 {
+		target = 1
+	}
+
+	seen := 0
+	for i, count := range stats.Histogram {
+		seen += count
+		if seen >= target {
+			if i < len(Buckets) && Buckets[i] < stats.Max {
+				return Buckets[i]
+			}
+			return stats.Max
+		}
+	}
+
+	return stats.Max
+}
+
+// HistogramString - the histogram formatted as bucket:count pairs, empty buckets are omitted
+func (stats CallStats) HistogramString() string {
+	var parts []string
+	for i, count := range stats.Histogram {
+		if count == 0 {
+			continue
+		}
+		label := fmt.Sprintf(">%v", Buckets[len(Buckets)-1])
+		if i < len(Buckets) {
+			label = fmt.Sprintf("<=%v", Buckets[i])
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d", label, count))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Report - a table of all JSON-RPC calls per method, endpoint and outcome
+func (metrics *RPCMetrics) Report() string {
+	stats := metrics.Stats()
+
+	var builder strings.Builder
+	format := "%-45s %-30s %-16s %-8s %-12s %-12s %-12s %-12s %s\n"
+	builder.WriteString(fmt.Sprintf(format, "Method", "Endpoint", "Outcome", "Calls", "Avg", "p50", "p95", "Max", "Total"))
+	builder.WriteString(fmt.Sprintf("%s\n", strings.Repeat("-", 50)))
+
+	totalCalls := 0
+	var totalTime time.Duration
+	for _, callStats := range stats {
+		totalCalls += callStats.Count
+		totalTime += callStats.Total
+
+		builder.WriteString(fmt.Sprintf(format,
+			callStats.Method,
+			callStats.Endpoint,
+			callStats.Outcome,
+			fmt.Sprintf("%d", callStats.Count),
+			round(callStats.Average()),
+			round(callStats.Percentile(50)),
+			round(callStats.Percentile(95)),
+			round(callStats.Max),
+			round(callStats.Total),
+		))
+	}
+
+	builder.WriteString(fmt.Sprintf("%s\n", strings.Repeat("-", 50)))
+	builder.WriteString(fmt.Sprintf("Total calls: %d\n", totalCalls))
+	builder.WriteString(fmt.Sprintf("Total time spent in calls: %v\n", round(totalTime)))
+
+	return builder.String()
+}
+
+// SleepMetrics - time spent in framework sleeps per reason
+type SleepMetrics struct {
+	mutex   sync.Mutex
+	reasons map[string]*SleepStats
+}
+
+// SleepStats - the number of sleeps and the time slept for a given reason
+type SleepStats struct {
+	Reason string
+	Count  int
+	Total  time.Duration
+}
+
+// Sleep - sleeps for a given duration and records it for a given reason
+func Sleep(reason string, duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+
+	time.Sleep(duration)
+	Sleeps.Observe(reason, duration)
+}
+
+// Observe - records time spent sleeping for a given reason
+func (metrics *SleepMetrics) Observe(reason string, duration time.Duration) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	stats, ok := metrics.reasons[reason]
+	if !ok {
+		stats = &SleepStats{Reason: reason}
+		metrics.reasons[reason] = stats
+	}
+
+	stats.Count++
+	stats.Total += duration
+}
+
+// Stats - all recorded sleep stats sorted by the total time slept
+func (metrics *SleepMetrics) Stats() (stats []SleepStats) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+
+	for _, sleepStats := range metrics.reasons {
+		stats = append(stats, *sleepStats)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Total != stats[j].Total {
+			return stats[i].Total > stats[j].Total
+		}
+		return stats[i].Reason < stats[j].Reason
+	})
+
+	return stats
+}
+
+// Report - a table of the time slept per reason
+// Sleeps of concurrent goroutines are summed up, the total can therefore exceed the duration of the run
+func (metrics *SleepMetrics) Report() string {
+	var builder strings.Builder
+	format := "%-45s %-8s %s\n"
+	builder.WriteString(fmt.Sprintf(format, "Reason", "Sleeps", "Total"))
+	builder.WriteString(fmt.Sprintf("%s\n", strings.Repeat("-", 50)))
+
+	var total time.Duration
+	for _, stats := range metrics.Stats() {
+		total += stats.Total
+		builder.WriteString(fmt.Sprintf(format, stats.Reason, fmt.Sprintf("%d", stats.Count), round(stats.Total)))
+	}
+
+	builder.WriteString(fmt.Sprintf("%s\n", strings.Repeat("-", 50)))
+	builder.WriteString(fmt.Sprintf("Total time slept: %v\n", round(total)))
+
+	return builder.String()
+}
+
+func bucket(latency time.Duration) int {
+	for i, upperBound := range Buckets {
+		if latency <= upperBound {
+			return i
+		}
+	}
+
+	return len(Buckets)
+}
+
+func round(duration time.Duration) time.Duration {
+	if duration < time.Second {
+		return duration.Round(100 * time.Microsecond)
+	}
+
+	return duration.Round(time.Millisecond)
+}
@@ -0,0 +1,278 @@
+package mocknode
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/harmony-one/go-sdk/pkg/address"
+	"github.com/harmony-one/harmony/common/denominations"
+	"github.com/harmony-one/harmony/numeric"
+	hmyStaking "github.com/harmony-one/harmony/staking/types"
+)
+
+// Options - settings for a mock chain
+type Options struct {
+	Shards         int
+	ChainID        *big.Int
+	Host           string
+	Port           int           // Port of the shard 0 server - shard n listens on Port+n. 0 picks random ports
+	BlockTime      time.Duration // How often empty blocks are produced - cross shard transfers are settled in the next block of the receiving shard
+	BlocksPerEpoch uint64
+	Genesis        map[string]numeric.Dec // Initial balances (in ONE) - every address is funded in every shard
+}
+
+// Chain - an in-memory simulation of a sharded Harmony chain
+type Chain struct {
+	Options       Options
+	URLs          []string
+	WebSocketURLs []string
+
+	mutex             sync.Mutex
+	shards            []*shard
+	receipts          map[ethCommon.Hash]map[string]interface{}
+	validators        map[ethCommon.Address]*hmyStaking.ValidatorWrapper
+	validatorOrder    []ethCommon.Address
+	txFailures        []failure
+	stakingFailures   []failure
+	crossShard        []crossShardTransfer
+	servers           []*http.Server
+	connections       map[*connection]bool
+	subscriptions     map[string]*subscription
+	subscriptionCount uint64
+	stop              chan struct{}
+}
+
+type shard struct {
+	id          uint32
+	blockNumber uint64
+	balances    map[ethCommon.Address]*big.Int
+	nonces      map[ethCommon.Address]uint64
+	queued      map[ethCommon.Address]map[uint64]queuedTransaction
+
+	// Hashes of the (staking) transactions per block
+	transactions        map[uint64][]string
+	stakingTransactions map[uint64][]string
+}
+
+type crossShardTransfer struct {
+	toShardID uint32
+	to        ethCommon.Address
+	amount    *big.Int
+}
+
+type failure struct {
+	DirectiveKind   string `json:"directive-kind,omitempty"`
+	ErrorMessage    string `json:"error-message"`
+	TimeAtRejection uint32 `json:"time-at-rejection"`
+	TxHashID        string `json:"tx-hash-id"`
+}
+
+var oneAsBigInt = big.NewInt(denominations.One)
+
+// New - creates a new mock chain
+func New(options Options) (*Chain, error) {
+	if options.Shards <= 0 {
+		options.Shards = 2
+	}
+	if options.ChainID == nil {
+		options.ChainID = big.NewInt(2)
+	}
+	if options.Host == "" {
+		options.Host = "127.0.0.1"
+	}
+	if options.BlockTime <= 0 {
+		options.BlockTime = time.Second
+	}
+	if options.BlocksPerEpoch == 0 {
+		options.BlocksPerEpoch = 30
+	}
+
+	chain := &Chain{
+		Options:       options,
+		receipts:      make(map[ethCommon.Hash]map[string]interface{}),
+		validators:    make(map[ethCommon.Address]*hmyStaking.ValidatorWrapper),
+		connections:   make(map[*connection]bool),
+		subscriptions: make(map[string]*subscription),
+		stop:          make(chan struct{}),
+	}
+
+	for shardID := 0; shardID < options.Shards; shardID++ {
+		chain.shards = append(chain.shards, &shard{
+			id:       uint32(shardID),
+			balances: make(map[ethCommon.Address]*big.Int),
+			nonces:   make(map[ethCommon.Address]uint64),
+			queued:   make(map[ethCommon.Address]map[uint64]queuedTransaction),
+
+			transactions:        make(map[uint64][]string),
+			stakingTransactions: make(map[uint64][]string),
+		})
+	}
+
+	for addr, amount := range options.Genesis {
+		for _, shard := range chain.shards {
+			shard.credit(address.Parse(addr), toAtto(amount))
+		}
+	}
+
+	return chain, nil
+}
+
+// Start - starts a JSON-RPC server per shard as well as the block production
+// Every server also accepts WebSocket connections - if a port is set, WebSocket servers additionally listen on port+300 just like Harmony nodes
+func (chain *Chain) Start() error {
+	for _, shard := range chain.shards {
+		port := 0
+		if chain.Options.Port > 0 {
+			port = chain.Options.Port + int(shard.id)
+		}
+
+		handler := &shardHandler{chain: chain, shardID: shard.id}
+		address, err := chain.serve(handler, port)
+		if err != nil {
+			chain.Stop()
+			return err
+		}
+		chain.URLs = append(chain.URLs, fmt.Sprintf("http://%s", address))
+
+		if port > 0 {
+			if address, err = chain.serve(handler, port+webSocketPortOffset); err != nil {
+				chain.Stop()
+				return err
+			}
+		}
+		chain.WebSocketURLs = append(chain.WebSocketURLs, fmt.Sprintf("ws://%s", address))
+	}
+
+	go chain.produceBlocks()
+
+	return nil
+}
+
+// Stop - stops all servers and the block production
+func (chain *Chain) Stop() {
+	select {
+	case <-chain.stop:
+	default:
+		close(chain.stop)
+	}
+
+	for _, server := range chain.servers {
+		server.Close()
+	}
+	chain.DropSubscriptions()
+}
+
+func (chain *Chain) serve(handler http.Handler, port int) (string, error) {
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", chain.Options.Host, port))
+	if err != nil {
+		return "", err
+	}
+
+	server := &http.Server{Handler: handler}
+	chain.servers = append(chain.servers, server)
+
+	go server.Serve(listener)
+
+	return listener.Addr().String(), nil
+}
+
+// Fund - credits a given amount (in ONE) to an address in a given shard
+func (chain *Chain) Fund(addr string, shardID uint32, amount numeric.Dec) error {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+
+	shard, err := chain.shard(shardID)
+	if err != nil {
+		return err
+	}
+
+	shard.credit(address.Parse(addr), toAtto(amount))
+	chain.nextBlock(shard)
+
+	return nil
+}
+
+func (chain *Chain) produceBlocks() {
+	ticker := time.NewTicker(chain.Options.BlockTime)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			chain.mutex.Lock()
+			for _, shard := range chain.shards {
+				chain.nextBlock(shard)
+			}
+			chain.mutex.Unlock()
+		case <-chain.stop:
+			return
+		}
+	}
+}
+
+// nextBlock - produces a new block for a given shard, settling pending cross shard transfers and unlocked undelegations
+func (chain *Chain) nextBlock(shard *shard) {
+	previousEpoch := chain.epoch(shard)
+	shard.blockNumber++
+
+	pending := chain.crossShard[:0]
+	for _, transfer := range chain.crossShard {
+		if transfer.toShardID == shard.id {
+			shard.credit(transfer.to, transfer.amount)
+		} else {
+			pending = append(pending, transfer)
+		}
+	}
+	chain.crossShard = pending
+
+	if shard.id == 0 && chain.epoch(shard).Cmp(previousEpoch) > 0 {
+		chain.releaseUndelegations(shard)
+	}
+
+	chain.notify(shard.id, newHeads, map[string]interface{}{
+		"number":     hexutil.EncodeUint64(shard.blockNumber),
+		"hash":       blockHash(shard.id, shard.blockNumber).Hex(),
+		"parentHash": blockHash(shard.id, shard.blockNumber-1).Hex(),
+		"shardID":    shard.id,
+		"epoch":      hexutil.EncodeBig(chain.epoch(shard)),
+		"timestamp":  hexutil.EncodeUint64(uint64(time.Now().Unix())),
+	})
+}
+
+func (chain *Chain) epoch(shard *shard) *big.Int {
+	return new(big.Int).SetUint64(shard.blockNumber / chain.Options.BlocksPerEpoch)
+}
+
+func (chain *Chain) shard(shardID uint32) (*shard, error) {
+	if int(shardID) >= len(chain.shards) {
+		return nil, fmt.Errorf("shard %d doesn't exist - the chain only has %d shard(s)", shardID, len(chain.shards))
+	}
+
+	return chain.shards[shardID], nil
+}
+
+func (shard *shard) balance(addr ethCommon.Address) *big.Int {
+	if balance, ok := shard.balances[addr]; ok {
+		return new(big.Int).Set(balance)
+	}
+
+	return big.NewInt(0)
+}
+
+func (shard *shard) credit(addr ethCommon.Address, amount *big.Int) {
+	shard.balances[addr] = new(big.Int).Add(shard.balance(addr), amount)
+}
+
+func (shard *shard) debit(addr ethCommon.Address, amount *big.Int) {
+	shard.balances[addr] = new(big.Int).Sub(shard.balance(addr), amount)
+}
+
+func toAtto(amount numeric.Dec) *big.Int {
+	return amount.Mul(numeric.NewDecFromBigInt(oneAsBigInt)).TruncateInt()
+}
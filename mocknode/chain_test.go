@@ -0,0 +1,193 @@
+package mocknode_test
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/go-sdk/pkg/address"
+	"github.com/harmony-one/harmony-tf/balances"
+	"github.com/harmony-one/harmony-tf/mocknode"
+	"github.com/harmony-one/harmony-tf/mocknode/mocknodetest"
+	"github.com/harmony-one/harmony-tf/network"
+	"github.com/harmony-one/harmony-tf/waits"
+	"github.com/harmony-one/harmony/core/types"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+const (
+	gasLimit    = 21000
+	receiptWait = 5 * time.Second
+)
+
+var (
+	chainID  = big.NewInt(2)
+	gasPrice = big.NewInt(1e9)
+)
+
+type signer struct {
+	key     *ecdsa.PrivateKey
+	address string
+}
+
+func newSigner(t *testing.T) signer {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signer{key: key, address: address.ToBech32(crypto.PubkeyToAddress(key.PublicKey))}
+}
+
+func startChain(t *testing.T, genesis map[string]numeric.Dec) *mocknode.Chain {
+	return mocknodetest.Start(t, mocknode.Options{ChainID: chainID, Shards: 2, BlockTime: 250 * time.Millisecond, Genesis: genesis})
+}
+
+// send - signs a transfer of a given amount of ONE and submits it to the mock chain
+func send(t *testing.T, chain *mocknode.Chain, from signer, nonce uint64, to string, fromShardID uint32, toShardID uint32, amount int64) string {
+	t.Helper()
+
+	receiver := ethCommon.Address(address.Parse(to))
+	tx := types.NewCrossShardTransaction(nonce, &receiver, fromShardID, toShardID, toAtto(amount), gasLimit, gasPrice, nil)
+
+	signed, err := types.SignTx(tx, types.NewEIP155Signer(chainID), from.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := chain.SendRawTransaction(fromShardID, hexutil.Encode(encoded))
+	if err != nil {
+		t.Fatalf("failed to send the transaction: %s", err.Error())
+	}
+
+	return hash.Hex()
+}
+
+func toAtto(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), big.NewInt(1e18))
+}
+
+// fee - the fee of a plain transfer in ONE
+func fee() numeric.Dec {
+	return numeric.NewDecFromBigIntWithPrec(new(big.Int).Mul(big.NewInt(gasLimit), gasPrice), 18)
+}
+
+func balance(t *testing.T, addr string, shardID uint32) numeric.Dec {
+	t.Helper()
+
+	balance, err := balances.GetShardBalance(addr, shardID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return balance
+}
+
+func awaitReceipt(t *testing.T, shardID uint32, txHash string) {
+	t.Helper()
+
+	if _, err := waits.Confirmations(shardID, txHash, 0, receiptWait); err != nil {
+		t.Fatalf("transaction %s wasn't included in shard %d: %s", txHash, shardID, err.Error())
+	}
+}
+
+func hasReceipt(t *testing.T, chain *mocknode.Chain, shardID uint32, txHash string) bool {
+	t.Helper()
+
+	_, found, err := network.TransactionBlockNumber(chain.URLs[shardID], txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return found
+}
+
+func TestSameShardTransfer(t *testing.T) {
+	sender, receiver := newSigner(t), newSigner(t)
+	chain := startChain(t, map[string]numeric.Dec{sender.address: numeric.NewDec(10)})
+
+	awaitReceipt(t, 0, send(t, chain, sender, 0, receiver.address, 0, 0, 3))
+
+	if got := balance(t, receiver.address, 0); !got.Equal(numeric.NewDec(3)) {
+		t.Errorf("expected the receiver to have a balance of 3, got %f", got)
+	}
+	if got, expected := balance(t, sender.address, 0), numeric.NewDec(7).Sub(fee()); !got.Equal(expected) {
+		t.Errorf("expected the sender to have a balance of %f, got %f", expected, got)
+	}
+	if got := balance(t, sender.address, 1); !got.Equal(numeric.NewDec(10)) {
+		t.Errorf("expected the sender's balance in shard 1 to be unaffected, got %f", got)
+	}
+}
+
+func TestCrossShardTransfer(t *testing.T) {
+	sender, receiver := newSigner(t), newSigner(t)
+	chain := startChain(t, map[string]numeric.Dec{sender.address: numeric.NewDec(10)})
+
+	awaitReceipt(t, 0, send(t, chain, sender, 0, receiver.address, 0, 1, 4))
+
+	if got := balance(t, receiver.address, 0); !got.IsZero() {
+		t.Errorf("expected the receiver to have no balance in the source shard, got %f", got)
+	}
+
+	// Cross shard transfers are settled in the next block of the receiving shard
+	if _, err := waits.Blocks(1, 1, receiptWait); err != nil {
+		t.Fatal(err)
+	}
+	if got := balance(t, receiver.address, 1); !got.Equal(numeric.NewDec(4)) {
+		t.Errorf("expected the receiver to have a balance of 4 in shard 1, got %f", got)
+	}
+	if got, expected := balance(t, sender.address, 0), numeric.NewDec(6).Sub(fee()); !got.Equal(expected) {
+		t.Errorf("expected the sender to have a balance of %f, got %f", expected, got)
+	}
+}
+
+func TestNonceGapIsQueued(t *testing.T) {
+	sender, receiver := newSigner(t), newSigner(t)
+	chain := startChain(t, map[string]numeric.Dec{sender.address: numeric.NewDec(10)})
+
+	queued := send(t, chain, sender, 1, receiver.address, 0, 0, 2)
+	if _, err := waits.Blocks(0, 2, receiptWait); err != nil {
+		t.Fatal(err)
+	}
+	if hasReceipt(t, chain, 0, queued) {
+		t.Fatal("expected the transaction with a nonce gap to be queued instead of executed")
+	}
+	if got := balance(t, receiver.address, 0); !got.IsZero() {
+		t.Fatalf("expected the queued transaction not to transfer any funds, got %f", got)
+	}
+
+	awaitReceipt(t, 0, send(t, chain, sender, 0, receiver.address, 0, 0, 1))
+	awaitReceipt(t, 0, queued)
+
+	if got := balance(t, receiver.address, 0); !got.Equal(numeric.NewDec(3)) {
+		t.Errorf("expected both transactions to be executed once the gap was filled, got a balance of %f", got)
+	}
+}
+
+func TestNonceTooLowIsRejected(t *testing.T) {
+	sender, receiver := newSigner(t), newSigner(t)
+	chain := startChain(t, map[string]numeric.Dec{sender.address: numeric.NewDec(10)})
+
+	awaitReceipt(t, 0, send(t, chain, sender, 0, receiver.address, 0, 0, 1))
+	replayed := send(t, chain, sender, 0, receiver.address, 0, 0, 2)
+
+	if _, err := waits.Blocks(0, 1, receiptWait); err != nil {
+		t.Fatal(err)
+	}
+	if hasReceipt(t, chain, 0, replayed) {
+		t.Error("expected the transaction reusing a nonce to be rejected")
+	}
+	if got := balance(t, receiver.address, 0); !got.Equal(numeric.NewDec(1)) {
+		t.Errorf("expected only the first transaction to transfer funds, got a balance of %f", got)
+	}
+}
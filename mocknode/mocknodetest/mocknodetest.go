@@ -0,0 +1,86 @@
+// Package mocknodetest - helpers for testing packages against an in-process mock chain
+package mocknodetest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/mocknode"
+	"github.com/harmony-one/harmony-tf/network"
+	"github.com/harmony-one/harmony/numeric"
+	"github.com/mitchellh/go-homedir"
+)
+
+// Start - starts a mock chain on random ports and configures the framework to run against it using the repository's config.yml
+// HOME points to a temporary directory while the test runs - the network, the chain and all generated state are torn down once the test has finished
+func Start(t *testing.T, options mocknode.Options) *mocknode.Chain {
+	t.Helper()
+
+	chain, err := mocknode.New(options)
+	if err != nil {
+		t.Fatalf("failed to create the mock chain: %s", err.Error())
+	}
+	if err := chain.Start(); err != nil {
+		t.Fatalf("failed to start the mock chain: %s", err.Error())
+	}
+	t.Cleanup(chain.Stop)
+
+	basePath, err := ioutil.TempDir("", "harmony-tf")
+	if err != nil {
+		t.Fatalf("failed to create the base path: %s", err.Error())
+	}
+	t.Cleanup(func() { os.RemoveAll(basePath) })
+
+	// Keystore accounts generated by the test must never end up in the keystore of the user running the tests
+	home := os.Getenv("HOME")
+	os.Setenv("HOME", basePath)
+	homedir.Reset()
+	t.Cleanup(func() {
+		os.Setenv("HOME", home)
+		homedir.Reset()
+	})
+
+	data, err := ioutil.ReadFile(filepath.Join(repositoryPath(), "config.yml"))
+	if err != nil {
+		t.Fatalf("failed to read config.yml: %s", err.Error())
+	}
+	if err := ioutil.WriteFile(filepath.Join(basePath, "config.yml"), data, 0600); err != nil {
+		t.Fatalf("failed to write config.yml: %s", err.Error())
+	}
+
+	// The mock chain uses the chain id of localnet unless told otherwise - custom mode uses the mock's urls as-is
+	config.Args = config.CommandArguments{Network: "localnet", Mode: "custom", Nodes: chain.URLs}
+	if err := config.Configure(basePath); err != nil {
+		t.Fatalf("failed to configure the framework: %s", err.Error())
+	}
+	config.Configuration.Network.Health.Enabled = false
+	config.Configuration.Network.Balances.Retry.Wait = 1
+
+	if err := network.Setup(); err != nil {
+		t.Fatalf("failed to set up the network: %s", err.Error())
+	}
+	t.Cleanup(func() { network.Stop() })
+
+	return chain
+}
+
+// UseFaucet - tops up the funding account to a given target from the faucet endpoint of the mock chain
+func UseFaucet(chain *mocknode.Chain, target numeric.Dec) {
+	config.Configuration.Funding.Sources = []string{"faucet"}
+	config.Configuration.Funding.Faucet = config.Faucet{
+		URL:         chain.URLs[0] + "/faucet",
+		Method:      "POST",
+		Body:        `{"address":"{address}","shard":{shard},"amount":"{amount}"}`,
+		MaxRequests: 1,
+	}
+	config.Configuration.Funding.Target = target
+}
+
+func repositoryPath() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..")
+}
@@ -0,0 +1,388 @@
+package mocknode
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/websocket"
+	"github.com/harmony-one/go-sdk/pkg/address"
+	"github.com/harmony-one/harmony/numeric"
+	"github.com/harmony-one/harmony/staking/effective"
+	hmyStaking "github.com/harmony-one/harmony/staking/types"
+)
+
+type shardHandler struct {
+	chain   *Chain
+	shardID uint32
+}
+
+type rpcRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      interface{}       `json:"id"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type rpcResponse struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      interface{} `json:"id"`
+	Result  interface{} `json:"result"`
+	Error   *rpcError   `json:"error,omitempty"`
+}
+
+type methodNotFoundError struct {
+	method string
+}
+
+func (err methodNotFoundError) Error() string {
+	return fmt.Sprintf("the method %s does not exist/is not available", err.method)
+}
+
+// ServeHTTP - answers JSON-RPC requests for the shard as well as faucet requests on /faucet
+func (handler *shardHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if strings.TrimSuffix(request.URL.Path, "/") == "/faucet" {
+		handler.serveFaucet(writer, request)
+		return
+	}
+
+	if websocket.IsWebSocketUpgrade(request) {
+		handler.serveWebSocket(writer, request)
+		return
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var rpc rpcRequest
+	if err := json.Unmarshal(body, &rpc); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := handler.call(rpc.Method, rpc.Params)
+
+	writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(writer).Encode(respond(rpc.ID, result, err))
+}
+
+// respond - wraps a result or an error into a JSON-RPC response
+func respond(id interface{}, result interface{}, err error) rpcResponse {
+	response := rpcResponse{JSONRPC: "2.0", ID: id}
+	if err != nil {
+		code := -32000
+		if _, ok := err.(methodNotFoundError); ok {
+			code = -32601
+		}
+		response.Error = &rpcError{Code: code, Message: err.Error()}
+	} else {
+		response.Result = result
+	}
+
+	return response
+}
+
+func (handler *shardHandler) call(method string, params []json.RawMessage) (interface{}, error) {
+	chain := handler.chain
+
+	switch method {
+	case "hmy_sendRawTransaction":
+		hash, err := chain.SendRawTransaction(handler.shardID, stringParam(params, 0))
+		if err != nil {
+			return nil, err
+		}
+		return hash.Hex(), nil
+	case "hmy_sendRawStakingTransaction":
+		hash, err := chain.SendRawStakingTransaction(handler.shardID, stringParam(params, 0))
+		if err != nil {
+			return nil, err
+		}
+		return hash.Hex(), nil
+	}
+
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+
+	shard := chain.shards[handler.shardID]
+
+	switch method {
+	case "hmy_getShardingStructure":
+		routes := []map[string]interface{}{}
+		for shardID, url := range chain.URLs {
+			routes = append(routes, map[string]interface{}{
+				"current": uint32(shardID) == handler.shardID,
+				"http":    url,
+				"shardID": shardID,
+				"ws":      chain.WebSocketURLs[shardID],
+			})
+		}
+		return routes, nil
+	case "hmy_getNodeMetadata":
+		return map[string]interface{}{
+			"shard-id":      handler.shardID,
+			"network":       "mocknet",
+			"version":       "harmony-tf mock node",
+			"is-leader":     true,
+			"role":          "Validator",
+			"current-epoch": chain.epoch(shard).Uint64(),
+			"chain-config": map[string]interface{}{
+				"chain-id": chain.Options.ChainID,
+			},
+		}, nil
+	case "hmy_getShardID":
+		return handler.shardID, nil
+	case "hmy_protocolVersion":
+		return "0x1", nil
+	case "hmy_syncing":
+		return false, nil
+	case "hmy_gasPrice":
+		return "0x3b9aca00", nil
+	case "hmy_blockNumber":
+		return hexutil.EncodeUint64(shard.blockNumber), nil
+	case "hmy_getEpoch":
+		return hexutil.EncodeBig(chain.epoch(shard)), nil
+	case "hmy_latestHeader":
+		return map[string]interface{}{
+			"blockHash":   blockHash(shard.id, shard.blockNumber).Hex(),
+			"blockNumber": shard.blockNumber,
+			"shardID":     shard.id,
+			"epoch":       chain.epoch(shard).Uint64(),
+			"viewID":      shard.blockNumber,
+			"timestamp":   time.Now().UTC().String(),
+		}, nil
+	case "hmy_getBlockByNumber":
+		blockNumber, err := uint64Param(params, 0, shard.blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		if blockNumber > shard.blockNumber {
+			return nil, nil
+		}
+		return map[string]interface{}{
+			"number":              hexutil.EncodeUint64(blockNumber),
+			"hash":                blockHash(shard.id, blockNumber).Hex(),
+			"parentHash":          blockHash(shard.id, blockNumber-1).Hex(),
+			"epoch":               hexutil.EncodeUint64(blockNumber / chain.Options.BlocksPerEpoch),
+			"shardID":             shard.id,
+			"timestamp":           hexutil.EncodeUint64(uint64(time.Now().Unix())),
+			"transactions":        append([]string{}, shard.transactions[blockNumber]...),
+			"stakingTransactions": append([]string{}, shard.stakingTransactions[blockNumber]...),
+		}, nil
+	case "hmy_getBlockTransactionCountByNumber":
+		blockNumber, err := uint64Param(params, 0, shard.blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		return hexutil.EncodeUint64(uint64(len(shard.transactions[blockNumber]))), nil
+	case "hmy_getBalance":
+		return hexutil.EncodeBig(shard.balance(address.Parse(stringParam(params, 0)))), nil
+	case "hmy_getTransactionCount":
+		return hexutil.EncodeUint64(shard.nonces[address.Parse(stringParam(params, 0))]), nil
+	case "hmy_getTransactionReceipt":
+		receipt, ok := chain.receipts[hashParam(params, 0)]
+		if !ok || receipt["shardID"] != handler.shardID {
+			return nil, nil
+		}
+		return receipt, nil
+	case "hmy_getCurrentTransactionErrorSink":
+		return append([]failure{}, chain.txFailures...), nil
+	case "hmy_getCurrentStakingErrorSink":
+		return append([]failure{}, chain.stakingFailures...), nil
+	case "hmy_getAllValidatorAddresses", "hmy_getElectedValidatorAddresses":
+		addresses := []string{}
+		for _, validatorAddress := range chain.validatorOrder {
+			if method == "hmy_getAllValidatorAddresses" || chain.validators[validatorAddress].Status == effective.Active {
+				addresses = append(addresses, address.ToBech32(validatorAddress))
+			}
+		}
+		return addresses, nil
+	case "hmy_getValidatorInformation", "hmy_getValidatorInformationByBlockNumber":
+		wrapper, ok := chain.validators[address.Parse(stringParam(params, 0))]
+		if !ok {
+			return nil, errValidatorNotFound
+		}
+		return chain.validatorInformation(wrapper), nil
+	case "hmy_getAllValidatorInformation", "hmy_getAllValidatorInformationByBlockNumber":
+		informations := []interface{}{}
+		if page, _ := uint64Param(params, 0, 0); page == 0 {
+			for _, validatorAddress := range chain.validatorOrder {
+				informations = append(informations, chain.validatorInformation(chain.validators[validatorAddress]))
+			}
+		}
+		return informations, nil
+	case "hmy_getDelegationsByDelegator", "hmy_getDelegationsByValidator":
+		target := address.Parse(stringParam(params, 0))
+		delegations := []interface{}{}
+		for _, validatorAddress := range chain.validatorOrder {
+			wrapper := chain.validators[validatorAddress]
+			for _, delegation := range wrapper.Delegations {
+				if (method == "hmy_getDelegationsByDelegator" && delegation.DelegatorAddress == target) || (method == "hmy_getDelegationsByValidator" && wrapper.Address == target) {
+					delegations = append(delegations, delegationInformation(wrapper, delegation))
+				}
+			}
+		}
+		return delegations, nil
+	}
+
+	return nil, methodNotFoundError{method: method}
+}
+
+// serveFaucet - credits funds to an address - accepts either a JSON body or query parameters with address, shard and amount (in ONE)
+func (handler *shardHandler) serveFaucet(writer http.ResponseWriter, request *http.Request) {
+	values := make(map[string]interface{})
+	for key := range request.URL.Query() {
+		values[key] = request.URL.Query().Get(key)
+	}
+	if request.Method != http.MethodGet {
+		body, _ := ioutil.ReadAll(request.Body)
+		if len(body) > 0 {
+			if err := json.Unmarshal(body, &values); err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	addr := fmt.Sprint(values["address"])
+	if _, ok := values["shard"]; !ok {
+		values["shard"] = handler.shardID
+	}
+	shardID, err := strconv.ParseUint(fmt.Sprint(values["shard"]), 10, 32)
+	if err != nil {
+		http.Error(writer, fmt.Sprintf("invalid shard: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	amount, err := numeric.NewDecFromStr(fmt.Sprint(values["amount"]))
+	if err != nil || !amount.IsPositive() {
+		http.Error(writer, fmt.Sprintf("invalid amount: %v", values["amount"]), http.StatusBadRequest)
+		return
+	}
+	if !strings.HasPrefix(addr, "one1") && !strings.HasPrefix(addr, "0x") {
+		http.Error(writer, fmt.Sprintf("invalid address: %s", addr), http.StatusBadRequest)
+		return
+	}
+
+	if err := handler.chain.Fund(addr, uint32(shardID), amount); err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(writer).Encode(map[string]interface{}{"success": true, "address": addr, "shard": shardID, "amount": amount.String()})
+}
+
+func (chain *Chain) validatorInformation(wrapper *hmyStaking.ValidatorWrapper) map[string]interface{} {
+	blsKeys := []string{}
+	for _, key := range wrapper.SlotPubKeys {
+		blsKeys = append(blsKeys, key.Hex())
+	}
+
+	delegations := []interface{}{}
+	for _, delegation := range wrapper.Delegations {
+		delegations = append(delegations, delegationInformation(wrapper, delegation))
+	}
+
+	eposStatus := "not eligible to be elected next epoch"
+	if wrapper.Status == effective.Active {
+		eposStatus = "currently elected"
+	}
+
+	return map[string]interface{}{
+		"validator": map[string]interface{}{
+			"address":                 address.ToBech32(wrapper.Address),
+			"bls-public-keys":         blsKeys,
+			"creation-height":         wrapper.CreationHeight,
+			"update-height":           wrapper.CreationHeight,
+			"min-self-delegation":     wrapper.MinSelfDelegation,
+			"max-total-delegation":    wrapper.MaxTotalDelegation,
+			"name":                    wrapper.Name,
+			"identity":                wrapper.Identity,
+			"website":                 wrapper.Website,
+			"security-contact":        wrapper.SecurityContact,
+			"details":                 wrapper.Details,
+			"rate":                    wrapper.Rate.String(),
+			"max-rate":                wrapper.MaxRate.String(),
+			"max-change-rate":         wrapper.MaxChangeRate.String(),
+			"epos-eligibility-status": wrapper.Status.String(),
+			"last-epoch-in-committee": wrapper.LastEpochInCommittee,
+			"delegations":             delegations,
+		},
+		"total-delegation":       wrapper.TotalDelegation(),
+		"currently-in-committee": wrapper.Status == effective.Active,
+		"epos-status":            eposStatus,
+	}
+}
+
+func delegationInformation(wrapper *hmyStaking.ValidatorWrapper, delegation hmyStaking.Delegation) map[string]interface{} {
+	undelegations := []interface{}{}
+	for _, undelegation := range delegation.Undelegations {
+		undelegations = append(undelegations, map[string]interface{}{
+			"Amount": undelegation.Amount,
+			"Epoch":  undelegation.Epoch,
+		})
+	}
+
+	reward := delegation.Reward
+	if reward == nil {
+		reward = big.NewInt(0)
+	}
+
+	return map[string]interface{}{
+		"validator_address": address.ToBech32(wrapper.Address),
+		"delegator_address": address.ToBech32(delegation.DelegatorAddress),
+		"amount":            delegation.Amount,
+		"reward":            reward,
+		"Undelegations":     undelegations,
+	}
+}
+
+func stringParam(params []json.RawMessage, index int) string {
+	if index >= len(params) {
+		return ""
+	}
+
+	var value string
+	if err := json.Unmarshal(params[index], &value); err != nil {
+		return strings.Trim(string(params[index]), `"`)
+	}
+
+	return value
+}
+
+func hashParam(params []json.RawMessage, index int) (hash ethCommon.Hash) {
+	decoded, err := hexutil.Decode(stringParam(params, index))
+	if err == nil {
+		copy(hash[:], decoded)
+	}
+
+	return hash
+}
+
+// uint64Param - parses a numeric param which can either be a hex string, a decimal string or a plain number - "latest" and missing params return fallback
+func uint64Param(params []json.RawMessage, index int, fallback uint64) (uint64, error) {
+	value := stringParam(params, index)
+	if value == "" || value == "latest" || value == "pending" {
+		return fallback, nil
+	}
+
+	if strings.HasPrefix(value, "0x") {
+		return hexutil.DecodeUint64(value)
+	}
+
+	return strconv.ParseUint(value, 10, 64)
+}
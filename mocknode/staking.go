@@ -0,0 +1,271 @@
+package mocknode
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/harmony-one/go-sdk/pkg/address"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/crypto/bls"
+	"github.com/harmony-one/harmony/staking/effective"
+	hmyStaking "github.com/harmony-one/harmony/staking/types"
+)
+
+var (
+	minimumDelegation = new(big.Int).Mul(oneAsBigInt, big.NewInt(100))
+
+	errValidatorNotFound = errors.New("staking validator does not exist")
+	errInvalidSigner     = errors.New("sender address is not the address of the staking message")
+)
+
+func (chain *Chain) executeStakingTransaction(shard *shard, from ethCommon.Address, tx *hmyStaking.StakingTransaction) error {
+	payload, err := tx.RLPEncodeStakeMsg()
+	if err != nil {
+		return err
+	}
+
+	gasUsed, err := core.IntrinsicGas(payload, false, true, tx.StakingType() == hmyStaking.DirectiveCreateValidator)
+	if err != nil {
+		return err
+	}
+	if gasUsed > tx.Gas() {
+		return fmt.Errorf("intrinsic gas too low: gas limit %d, required %d", tx.Gas(), gasUsed)
+	}
+
+	amount := big.NewInt(0)
+	switch msg := tx.StakingMessage().(type) {
+	case *hmyStaking.CreateValidator:
+		amount = msg.Amount
+	case *hmyStaking.Delegate:
+		amount = msg.Amount
+	}
+
+	maxCost := new(big.Int).Add(new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasPrice()), amount)
+	if shard.balance(from).Cmp(maxCost) < 0 {
+		return fmt.Errorf("insufficient funds for gas * price + value: balance %s, required %s", shard.balance(from), maxCost)
+	}
+
+	blockNumber := new(big.Int).SetUint64(shard.blockNumber + 1)
+	epoch := chain.epoch(shard)
+
+	var credit *big.Int
+	switch msg := tx.StakingMessage().(type) {
+	case *hmyStaking.CreateValidator:
+		err = chain.createValidator(from, msg, blockNumber, epoch)
+	case *hmyStaking.EditValidator:
+		err = chain.editValidator(from, msg, epoch)
+	case *hmyStaking.Delegate:
+		err = chain.delegate(from, msg)
+	case *hmyStaking.Undelegate:
+		err = chain.undelegate(from, msg, epoch)
+	case *hmyStaking.CollectRewards:
+		credit, err = chain.collectRewards(from, msg)
+	default:
+		err = hmyStaking.ErrInvalidStakingKind
+	}
+	if err != nil {
+		return err
+	}
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), tx.GasPrice())
+	shard.debit(from, new(big.Int).Add(amount, fee))
+	if credit != nil {
+		shard.credit(from, credit)
+	}
+	shard.nonces[from]++
+
+	chain.nextBlock(shard)
+	chain.receipt(shard, tx.Hash(), from, gasUsed)
+
+	return nil
+}
+
+func (chain *Chain) createValidator(from ethCommon.Address, msg *hmyStaking.CreateValidator, blockNumber *big.Int, epoch *big.Int) error {
+	if from != msg.ValidatorAddress {
+		return errInvalidSigner
+	}
+
+	if _, ok := chain.validators[msg.ValidatorAddress]; ok {
+		return errors.New("staking validator already exists")
+	}
+
+	for _, key := range msg.SlotPubKeys {
+		if err := chain.ensureUnusedBLSKey(key); err != nil {
+			return err
+		}
+	}
+
+	validator, err := hmyStaking.CreateValidatorFromNewMsg(msg, blockNumber, epoch)
+	if err != nil {
+		return err
+	}
+
+	wrapper := &hmyStaking.ValidatorWrapper{
+		Validator:   *validator,
+		Delegations: hmyStaking.Delegations{hmyStaking.NewDelegation(from, new(big.Int).Set(msg.Amount))},
+		BlockReward: big.NewInt(0),
+	}
+	if err := wrapper.SanityCheck(); err != nil {
+		return err
+	}
+
+	chain.validators[msg.ValidatorAddress] = wrapper
+	chain.validatorOrder = append(chain.validatorOrder, msg.ValidatorAddress)
+
+	return nil
+}
+
+func (chain *Chain) editValidator(from ethCommon.Address, msg *hmyStaking.EditValidator, epoch *big.Int) error {
+	if from != msg.ValidatorAddress {
+		return errInvalidSigner
+	}
+
+	wrapper, ok := chain.validators[msg.ValidatorAddress]
+	if !ok {
+		return errValidatorNotFound
+	}
+
+	if msg.SlotKeyToAdd != nil {
+		if err := chain.ensureUnusedBLSKey(*msg.SlotKeyToAdd); err != nil {
+			return err
+		}
+	}
+
+	updated := *wrapper
+	updated.SlotPubKeys = append([]bls.SerializedPublicKey{}, wrapper.SlotPubKeys...)
+	if err := hmyStaking.UpdateValidatorFromEditMsg(&updated.Validator, msg, epoch); err != nil {
+		return err
+	}
+
+	if err := updated.SanityCheck(); err != nil {
+		return err
+	}
+
+	*wrapper = updated
+
+	return nil
+}
+
+func (chain *Chain) delegate(from ethCommon.Address, msg *hmyStaking.Delegate) error {
+	if from != msg.DelegatorAddress {
+		return errInvalidSigner
+	}
+
+	wrapper, ok := chain.validators[msg.ValidatorAddress]
+	if !ok {
+		return errValidatorNotFound
+	}
+
+	if msg.Amount.Cmp(minimumDelegation) < 0 {
+		return fmt.Errorf("delegation amount %s is lower than the minimum delegation amount %s", msg.Amount, minimumDelegation)
+	}
+
+	updated := *wrapper
+	updated.Delegations = append(hmyStaking.Delegations{}, wrapper.Delegations...)
+
+	index := delegationIndex(updated.Delegations, from)
+	if index >= 0 {
+		updated.Delegations[index].Amount = new(big.Int).Add(updated.Delegations[index].Amount, msg.Amount)
+	} else {
+		updated.Delegations = append(updated.Delegations, hmyStaking.NewDelegation(from, new(big.Int).Set(msg.Amount)))
+	}
+
+	if err := updated.SanityCheck(); err != nil {
+		return err
+	}
+
+	*wrapper = updated
+
+	return nil
+}
+
+func (chain *Chain) undelegate(from ethCommon.Address, msg *hmyStaking.Undelegate, epoch *big.Int) error {
+	if from != msg.DelegatorAddress {
+		return errInvalidSigner
+	}
+
+	wrapper, ok := chain.validators[msg.ValidatorAddress]
+	if !ok {
+		return errValidatorNotFound
+	}
+
+	index := delegationIndex(wrapper.Delegations, from)
+	if index < 0 {
+		return errors.New("no delegation to undelegate")
+	}
+
+	delegation := wrapper.Delegations[index]
+	delegation.Amount = new(big.Int).Set(delegation.Amount)
+	delegation.Undelegations = append(hmyStaking.Undelegations{}, delegation.Undelegations...)
+	if err := delegation.Undelegate(epoch, msg.Amount); err != nil {
+		return err
+	}
+	wrapper.Delegations[index] = delegation
+
+	// A validator whose self delegation drops below the minimum self delegation is no longer eligible for election
+	if from == wrapper.Address && delegation.Amount.Cmp(wrapper.MinSelfDelegation) < 0 {
+		wrapper.Status = effective.Inactive
+	}
+
+	return nil
+}
+
+func (chain *Chain) collectRewards(from ethCommon.Address, msg *hmyStaking.CollectRewards) (*big.Int, error) {
+	if from != msg.DelegatorAddress {
+		return nil, errInvalidSigner
+	}
+
+	total := big.NewInt(0)
+	for _, validatorAddress := range chain.validatorOrder {
+		wrapper := chain.validators[validatorAddress]
+		if index := delegationIndex(wrapper.Delegations, from); index >= 0 {
+			total.Add(total, wrapper.Delegations[index].Reward)
+			wrapper.Delegations[index].Reward = big.NewInt(0)
+		}
+	}
+
+	if total.Sign() == 0 {
+		return nil, errors.New("no rewards to collect")
+	}
+
+	return total, nil
+}
+
+// releaseUndelegations - returns undelegated tokens to their delegators once the lock period has passed
+func (chain *Chain) releaseUndelegations(beacon *shard) {
+	epoch := chain.epoch(beacon)
+
+	for _, validatorAddress := range chain.validatorOrder {
+		wrapper := chain.validators[validatorAddress]
+		for i := range wrapper.Delegations {
+			released := wrapper.Delegations[i].RemoveUnlockedUndelegations(epoch, epoch, hmyStaking.LockPeriodInEpoch)
+			if released.Sign() > 0 {
+				beacon.credit(wrapper.Delegations[i].DelegatorAddress, released)
+			}
+		}
+	}
+}
+
+func (chain *Chain) ensureUnusedBLSKey(key bls.SerializedPublicKey) error {
+	for _, wrapper := range chain.validators {
+		for _, existing := range wrapper.SlotPubKeys {
+			if existing == key {
+				return fmt.Errorf("bls key %s is already used by validator %s", key.Hex(), address.ToBech32(wrapper.Address))
+			}
+		}
+	}
+
+	return nil
+}
+
+func delegationIndex(delegations hmyStaking.Delegations, delegator ethCommon.Address) int {
+	for i, delegation := range delegations {
+		if delegation.DelegatorAddress == delegator {
+			return i
+		}
+	}
+
+	return -1
+}
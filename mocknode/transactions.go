@@ -0,0 +1,228 @@
+package mocknode
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	ethCommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/harmony-one/go-sdk/pkg/address"
+	"github.com/harmony-one/harmony/core"
+	"github.com/harmony-one/harmony/core/types"
+	hmyStaking "github.com/harmony-one/harmony/staking/types"
+)
+
+type queuedTransaction struct {
+	transaction        *types.Transaction
+	stakingTransaction *hmyStaking.StakingTransaction
+}
+
+func (queued queuedTransaction) hash() ethCommon.Hash {
+	if queued.stakingTransaction != nil {
+		return queued.stakingTransaction.Hash()
+	}
+	return queued.transaction.Hash()
+}
+
+// SendRawTransaction - decodes a signed transaction and submits it to a given shard
+// Transactions with a nonce ahead of the account's current nonce are queued until the gap has been filled
+func (chain *Chain) SendRawTransaction(shardID uint32, encoded string) (ethCommon.Hash, error) {
+	data, err := hexutil.Decode(encoded)
+	if err != nil {
+		return ethCommon.Hash{}, err
+	}
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(data, tx); err != nil {
+		return ethCommon.Hash{}, fmt.Errorf("failed to decode transaction: %s", err.Error())
+	}
+
+	if tx.ShardID() != shardID {
+		return ethCommon.Hash{}, fmt.Errorf("transaction shard id %d doesn't match the shard id %d of this node", tx.ShardID(), shardID)
+	}
+
+	if tx.ChainID().Cmp(chain.Options.ChainID) != 0 {
+		return ethCommon.Hash{}, fmt.Errorf("invalid chain id %s for signer - expected chain id %s", tx.ChainID(), chain.Options.ChainID)
+	}
+
+	from, err := types.Sender(types.NewEIP155Signer(chain.Options.ChainID), tx)
+	if err != nil {
+		return ethCommon.Hash{}, err
+	}
+
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+
+	shard, err := chain.shard(shardID)
+	if err != nil {
+		return ethCommon.Hash{}, err
+	}
+
+	chain.submit(shard, from, tx.Nonce(), queuedTransaction{transaction: tx})
+
+	return tx.Hash(), nil
+}
+
+// SendRawStakingTransaction - decodes a signed staking transaction and submits it to the beacon shard
+func (chain *Chain) SendRawStakingTransaction(shardID uint32, encoded string) (ethCommon.Hash, error) {
+	data, err := hexutil.Decode(encoded)
+	if err != nil {
+		return ethCommon.Hash{}, err
+	}
+
+	tx := new(hmyStaking.StakingTransaction)
+	if err := rlp.DecodeBytes(data, tx); err != nil {
+		return ethCommon.Hash{}, fmt.Errorf("failed to decode staking transaction: %s", err.Error())
+	}
+
+	if shardID != 0 {
+		return ethCommon.Hash{}, errors.New("staking transactions are only accepted by the beacon chain (shard 0)")
+	}
+
+	if tx.ChainID().Cmp(chain.Options.ChainID) != 0 {
+		return ethCommon.Hash{}, fmt.Errorf("invalid chain id %s for signer - expected chain id %s", tx.ChainID(), chain.Options.ChainID)
+	}
+
+	from, err := hmyStaking.Sender(hmyStaking.NewEIP155Signer(chain.Options.ChainID), tx)
+	if err != nil {
+		return ethCommon.Hash{}, err
+	}
+
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+
+	chain.submit(chain.shards[0], from, tx.Nonce(), queuedTransaction{stakingTransaction: tx})
+
+	return tx.Hash(), nil
+}
+
+func (chain *Chain) submit(shard *shard, from ethCommon.Address, nonce uint64, queued queuedTransaction) {
+	chain.notify(shard.id, newPendingTransactions, queued.hash().Hex())
+
+	expected := shard.nonces[from]
+
+	switch {
+	case nonce < expected:
+		chain.reject(queued, fmt.Sprintf("transaction nonce is %d: nonce too low", nonce))
+	case nonce > expected:
+		if shard.queued[from] == nil {
+			shard.queued[from] = make(map[uint64]queuedTransaction)
+		}
+		shard.queued[from][nonce] = queued
+	default:
+		chain.execute(shard, from, queued)
+
+		for {
+			next, ok := shard.queued[from][shard.nonces[from]]
+			if !ok {
+				break
+			}
+			delete(shard.queued[from], shard.nonces[from])
+			chain.execute(shard, from, next)
+		}
+	}
+}
+
+// execute - executes a transaction - rejected transactions end up in the error sinks and don't consume the nonce
+func (chain *Chain) execute(shard *shard, from ethCommon.Address, queued queuedTransaction) {
+	var err error
+	if queued.stakingTransaction != nil {
+		err = chain.executeStakingTransaction(shard, from, queued.stakingTransaction)
+	} else {
+		err = chain.executeTransaction(shard, from, queued.transaction)
+	}
+
+	if err != nil {
+		chain.reject(queued, err.Error())
+		return
+	}
+
+	if queued.stakingTransaction != nil {
+		shard.stakingTransactions[shard.blockNumber] = append(shard.stakingTransactions[shard.blockNumber], queued.hash().Hex())
+	} else {
+		shard.transactions[shard.blockNumber] = append(shard.transactions[shard.blockNumber], queued.hash().Hex())
+	}
+}
+
+func (chain *Chain) executeTransaction(shard *shard, from ethCommon.Address, tx *types.Transaction) error {
+	if tx.To() == nil {
+		return errors.New("contract creation isn't supported by the mock node")
+	}
+
+	toShard, err := chain.shard(tx.ToShardID())
+	if err != nil {
+		return err
+	}
+
+	gasUsed, err := core.IntrinsicGas(tx.Data(), false, true, false)
+	if err != nil {
+		return err
+	}
+	if gasUsed > tx.Gas() {
+		return fmt.Errorf("intrinsic gas too low: gas limit %d, required %d", tx.Gas(), gasUsed)
+	}
+
+	maxCost := new(big.Int).Add(new(big.Int).Mul(new(big.Int).SetUint64(tx.Gas()), tx.GasPrice()), tx.Value())
+	if shard.balance(from).Cmp(maxCost) < 0 {
+		return fmt.Errorf("insufficient funds for gas * price + value: balance %s, required %s", shard.balance(from), maxCost)
+	}
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gasUsed), tx.GasPrice())
+	shard.debit(from, new(big.Int).Add(tx.Value(), fee))
+	shard.nonces[from]++
+
+	if toShard == shard {
+		shard.credit(*tx.To(), tx.Value())
+	} else {
+		chain.crossShard = append(chain.crossShard, crossShardTransfer{toShardID: toShard.id, to: *tx.To(), amount: tx.Value()})
+	}
+
+	chain.nextBlock(shard)
+	receipt := chain.receipt(shard, tx.Hash(), from, gasUsed)
+	receipt["to"] = address.ToBech32(*tx.To())
+	receipt["toShardID"] = toShard.id
+
+	return nil
+}
+
+func (chain *Chain) receipt(shard *shard, hash ethCommon.Hash, from ethCommon.Address, gasUsed uint64) map[string]interface{} {
+	receipt := map[string]interface{}{
+		"transactionHash":   hash.Hex(),
+		"transactionIndex":  "0x0",
+		"blockHash":         blockHash(shard.id, shard.blockNumber).Hex(),
+		"blockNumber":       hexutil.EncodeUint64(shard.blockNumber),
+		"from":              address.ToBech32(from),
+		"shardID":           shard.id,
+		"gasUsed":           hexutil.EncodeUint64(gasUsed),
+		"cumulativeGasUsed": hexutil.EncodeUint64(gasUsed),
+		"contractAddress":   nil,
+		"logs":              []interface{}{},
+		"status":            "0x1",
+	}
+	chain.receipts[hash] = receipt
+
+	return receipt
+}
+
+func (chain *Chain) reject(queued queuedTransaction, message string) {
+	rejection := failure{
+		ErrorMessage:    message,
+		TimeAtRejection: uint32(time.Now().Unix()),
+		TxHashID:        queued.hash().Hex(),
+	}
+
+	if queued.stakingTransaction != nil {
+		rejection.DirectiveKind = queued.stakingTransaction.StakingType().String()
+		chain.stakingFailures = append(chain.stakingFailures, rejection)
+	} else {
+		chain.txFailures = append(chain.txFailures, rejection)
+	}
+}
+
+func blockHash(shardID uint32, blockNumber uint64) ethCommon.Hash {
+	return crypto.Keccak256Hash([]byte(fmt.Sprintf("%d/%d", shardID, blockNumber)))
+}
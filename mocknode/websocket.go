@@ -0,0 +1,183 @@
+package mocknode
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	newHeads               = "newHeads"
+	newPendingTransactions = "newPendingTransactions"
+
+	// webSocketPortOffset - Harmony nodes serve WebSocket RPC on their HTTP port + 300 (9500 -> 9800)
+	webSocketPortOffset = 300
+)
+
+var upgrader = websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+
+// subscription - a newHeads or newPendingTransactions subscription of a WebSocket connection
+type subscription struct {
+	id         string
+	shardID    uint32
+	kind       string
+	connection *connection
+}
+
+// connection - a WebSocket connection - messages are written by a separate goroutine so that block production never blocks on slow clients
+type connection struct {
+	conn      *websocket.Conn
+	outbox    chan interface{}
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// serveWebSocket - answers JSON-RPC requests over a WebSocket connection and handles hmy_subscribe/hmy_unsubscribe
+func (handler *shardHandler) serveWebSocket(writer http.ResponseWriter, request *http.Request) {
+	conn, err := upgrader.Upgrade(writer, request, nil)
+	if err != nil {
+		return
+	}
+
+	chain := handler.chain
+	connection := chain.connect(conn)
+	defer chain.disconnect(connection)
+
+	go connection.write()
+
+	for {
+		var rpc rpcRequest
+		if err := conn.ReadJSON(&rpc); err != nil {
+			return
+		}
+
+		switch rpc.Method {
+		case "hmy_subscribe", "eth_subscribe":
+			chain.subscribe(connection, rpc.ID, handler.shardID, stringParam(rpc.Params, 0))
+		case "hmy_unsubscribe", "eth_unsubscribe":
+			connection.send(respond(rpc.ID, chain.unsubscribe(stringParam(rpc.Params, 0)), nil))
+		default:
+			result, err := handler.call(rpc.Method, rpc.Params)
+			connection.send(respond(rpc.ID, result, err))
+		}
+	}
+}
+
+// DropSubscriptions - closes all WebSocket connections, e.g. to test how clients handle dropped subscriptions
+func (chain *Chain) DropSubscriptions() {
+	chain.mutex.Lock()
+	connections := []*connection{}
+	for connection := range chain.connections {
+		connections = append(connections, connection)
+	}
+	chain.mutex.Unlock()
+
+	for _, connection := range connections {
+		connection.close()
+	}
+}
+
+func (chain *Chain) connect(conn *websocket.Conn) *connection {
+	connection := &connection{conn: conn, outbox: make(chan interface{}, 256), closed: make(chan struct{})}
+
+	chain.mutex.Lock()
+	chain.connections[connection] = true
+	chain.mutex.Unlock()
+
+	return connection
+}
+
+func (chain *Chain) disconnect(connection *connection) {
+	connection.close()
+
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+
+	delete(chain.connections, connection)
+	for id, subscription := range chain.subscriptions {
+		if subscription.connection == connection {
+			delete(chain.subscriptions, id)
+		}
+	}
+}
+
+// subscribe - registers a subscription and queues the response - both happen while holding the chain lock so that no notification can overtake the response
+func (chain *Chain) subscribe(connection *connection, requestID interface{}, shardID uint32, kind string) {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+
+	if kind != newHeads && kind != newPendingTransactions {
+		connection.send(respond(requestID, nil, fmt.Errorf("no %q subscription in hmy namespace", kind)))
+		return
+	}
+
+	chain.subscriptionCount++
+	id := hexutil.EncodeUint64(chain.subscriptionCount)
+	chain.subscriptions[id] = &subscription{id: id, shardID: shardID, kind: kind, connection: connection}
+
+	connection.send(respond(requestID, id, nil))
+}
+
+func (chain *Chain) unsubscribe(id string) bool {
+	chain.mutex.Lock()
+	defer chain.mutex.Unlock()
+
+	_, ok := chain.subscriptions[id]
+	delete(chain.subscriptions, id)
+
+	return ok
+}
+
+// notify - sends a notification to all subscriptions of a given kind for a given shard - the chain lock has to be held
+func (chain *Chain) notify(shardID uint32, kind string, result interface{}) {
+	for _, subscription := range chain.subscriptions {
+		if subscription.shardID == shardID && subscription.kind == kind {
+			subscription.connection.send(map[string]interface{}{
+				"jsonrpc": "2.0",
+				"method":  "hmy_subscription",
+				"params": map[string]interface{}{
+					"subscription": subscription.id,
+					"result":       result,
+				},
+			})
+		}
+	}
+}
+
+// send - queues a message - messages to clients that don't keep up are dropped
+func (connection *connection) send(message interface{}) {
+	select {
+	case <-connection.closed:
+		return
+	default:
+	}
+
+	select {
+	case connection.outbox <- message:
+	default:
+	}
+}
+
+func (connection *connection) write() {
+	for {
+		select {
+		case message := <-connection.outbox:
+			if err := connection.conn.WriteJSON(message); err != nil {
+				connection.close()
+				return
+			}
+		case <-connection.closed:
+			return
+		}
+	}
+}
+
+func (connection *connection) close() {
+	connection.closeOnce.Do(func() {
+		close(connection.closed)
+		connection.conn.Close()
+	})
+}
@@ -0,0 +1,331 @@
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+)
+
+const (
+	// SetupCassette - the cassette used for the traffic before the first test case (funding account setup etc.)
+	SetupCassette = "setup"
+
+	// TeardownCassette - the cassette used for the traffic after the last test case (reclaiming funds etc.)
+	TeardownCassette = "teardown"
+)
+
+var (
+	// Recorder - records JSON-RPC traffic to cassettes or replays it from them depending on network.cassettes.mode
+	Recorder = &CassetteRecorder{}
+
+	cassetteNameRegex = regexp.MustCompile(`[^a-z0-9]+`)
+	addressRegex      = regexp.MustCompile(`^(one1[02-9ac-hj-np-z]{38}|0x[0-9a-fA-F]{40})$`)
+	rawDataRegex      = regexp.MustCompile(`^0x[0-9a-fA-F]{67,}$`)
+)
+
+// Cassette - the JSON-RPC interactions recorded for a given test case
+type Cassette struct {
+	Name         string         `json:"name"`
+	Network      string         `json:"network"`
+	RecordedAt   time.Time      `json:"recorded_at"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction - a single recorded JSON-RPC request and its response
+type Interaction struct {
+	ShardID  uint32          `json:"shard_id"`
+	Node     string          `json:"node"`
+	Method   string          `json:"method"`
+	Params   json.RawMessage `json:"params"`
+	Response json.RawMessage `json:"response"`
+	used     bool
+}
+
+// CassetteRecorder - keeps track of the cassette for the test case that is currently executing
+type CassetteRecorder struct {
+	mutex    sync.Mutex
+	cassette *Cassette
+}
+
+// Enabled - whether or not JSON-RPC traffic is either recorded or replayed
+func (recorder *CassetteRecorder) Enabled() bool {
+	return recorder.Recording() || recorder.Replaying()
+}
+
+// Recording - whether or not JSON-RPC traffic is recorded
+func (recorder *CassetteRecorder) Recording() bool {
+	return config.Configuration.Network.Cassettes.Recording()
+}
+
+// Replaying - whether or not JSON-RPC traffic is answered from cassettes
+func (recorder *CassetteRecorder) Replaying() bool {
+	return config.Configuration.Network.Cassettes.Replaying()
+}
+
+// SetTestCase - switches to the cassette of a given test case (an empty name switches to the setup cassette)
+// When recording the previous cassette is saved, when replaying the cassette of the test case is loaded
+func (recorder *CassetteRecorder) SetTestCase(name string) error {
+	if !recorder.Enabled() {
+		return nil
+	}
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	if err := recorder.save(); err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = SetupCassette
+	}
+
+	if recorder.Replaying() {
+		cassette, err := LoadCassette(name)
+		if err != nil {
+			return err
+		}
+		if len(cassette.Interactions) == 0 {
+			logger.WarningLog(fmt.Sprintf("Couldn't find any recorded interactions for %s in %s", name, cassettePath(name)), true)
+		}
+		recorder.cassette = cassette
+	} else {
+		recorder.cassette = &Cassette{Name: name, Network: config.Configuration.Network.Name, RecordedAt: time.Now().UTC()}
+	}
+
+	return nil
+}
+
+// Close - saves the current cassette if recording
+func (recorder *CassetteRecorder) Close() error {
+	if !recorder.Enabled() {
+		return nil
+	}
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	err := recorder.save()
+	recorder.cassette = nil
+
+	return err
+}
+
+// Record - records a JSON-RPC request and the response it received
+func (recorder *CassetteRecorder) Record(shardID uint32, node string, payload []byte, response []byte) {
+	var request rpcRequest
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return
+	}
+
+	params, _ := json.Marshal(request.Params)
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	if recorder.cassette == nil {
+		recorder.cassette = &Cassette{Name: SetupCassette, Network: config.Configuration.Network.Name, RecordedAt: time.Now().UTC()}
+	}
+
+	recorder.cassette.Interactions = append(recorder.cassette.Interactions, &Interaction{
+		ShardID:  shardID,
+		Node:     node,
+		Method:   request.Method,
+		Params:   compact(params),
+		Response: compact(response),
+	})
+}
+
+// Replay - answers a JSON-RPC request using the current cassette
+// Interactions are matched on method and exact params first and then on params with non-deterministic values (addresses, signed payloads) masked - requests without a match result in an error
+func (recorder *CassetteRecorder) Replay(shardID uint32, payload []byte) ([]byte, error) {
+	var request rpcRequest
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return nil, err
+	}
+
+	params, _ := json.Marshal(request.Params)
+	params = compact(params)
+	masked := maskParams(params)
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	if recorder.cassette == nil {
+		return nil, fmt.Errorf("no cassette has been loaded")
+	}
+
+	var exact, normalized, previous *Interaction
+	for _, interaction := range recorder.cassette.Interactions {
+		if interaction.ShardID != shardID || interaction.Method != request.Method {
+			continue
+		}
+
+		sameParams := bytes.Equal(interaction.Params, params)
+		sameMaskedParams := sameParams || bytes.Equal(maskParams(interaction.Params), masked)
+
+		if interaction.used {
+			// Polling calls (e.g. receipts) can be made more times than they were recorded - fall back to the latest matching response
+			if sameParams || (previous == nil && sameMaskedParams) {
+				previous = interaction
+			}
+			continue
+		}
+
+		if sameParams {
+			exact = interaction
+			break
+		}
+		if normalized == nil && sameMaskedParams {
+			normalized = interaction
+		}
+	}
+
+	// Requests that don't even match on their masked params (e.g. a balance of another address) are never answered with an unrelated response
+	interaction := exact
+	for _, candidate := range []*Interaction{normalized, previous} {
+		if interaction == nil {
+			interaction = candidate
+		}
+	}
+
+	if interaction == nil {
+		return nil, fmt.Errorf("no recorded response for %s %s on shard %d in cassette %s", request.Method, string(params), shardID, recorder.cassette.Name)
+	}
+
+	if interaction != exact && interaction != previous {
+		logger.Log(fmt.Sprintf("Replaying %s %s using the recorded request %s", request.Method, string(params), string(interaction.Params)), config.Configuration.Framework.Verbose)
+	}
+	interaction.used = true
+
+	return withRequestID(interaction.Response, request.ID)
+}
+
+// LoadCassette - loads the cassette of a given test case - a missing cassette results in an empty cassette
+func LoadCassette(name string) (*Cassette, error) {
+	cassette := &Cassette{Name: name, Network: config.Configuration.Network.Name}
+
+	data, err := ioutil.ReadFile(cassettePath(name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cassette, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, err
+	}
+
+	return cassette, nil
+}
+
+// SaveShardingStructure - saves the current sharding structure so that the network can be set up without querying it when replaying
+func SaveShardingStructure() error {
+	data, err := json.MarshalIndent(config.Configuration.Network.API.ShardingStructure, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(config.Configuration.Network.Cassettes.Path, 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(config.Configuration.Network.Cassettes.ShardingStructurePath(), data, 0644)
+}
+
+func (recorder *CassetteRecorder) save() error {
+	if !recorder.Recording() || recorder.cassette == nil || len(recorder.cassette.Interactions) == 0 {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(recorder.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(config.Configuration.Network.Cassettes.Path, 0755); err != nil {
+		return err
+	}
+
+	path := cassettePath(recorder.cassette.Name)
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	logger.Log(fmt.Sprintf("Recorded %d JSON-RPC interactions to %s", len(recorder.cassette.Interactions), path), config.Configuration.Framework.Verbose)
+
+	return nil
+}
+
+func cassettePath(name string) string {
+	fileName := strings.Trim(cassetteNameRegex.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	return filepath.Join(config.Configuration.Network.Cassettes.Path, fmt.Sprintf("%s.json", fileName))
+}
+
+// maskParams - masks values that differ between runs (generated addresses, signed transactions) so that requests can be matched regardless of them
+func maskParams(params json.RawMessage) json.RawMessage {
+	var decoded interface{}
+	if err := json.Unmarshal(params, &decoded); err != nil {
+		return params
+	}
+
+	masked, err := json.Marshal(maskValue(decoded))
+	if err != nil {
+		return params
+	}
+
+	return masked
+}
+
+func maskValue(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case string:
+		if addressRegex.MatchString(typed) {
+			return "<address>"
+		}
+		if rawDataRegex.MatchString(typed) {
+			return "<raw>"
+		}
+	case []interface{}:
+		for i := range typed {
+			typed[i] = maskValue(typed[i])
+		}
+	case map[string]interface{}:
+		for key := range typed {
+			typed[key] = maskValue(typed[key])
+		}
+	}
+
+	return value
+}
+
+func withRequestID(response json.RawMessage, id interface{}) ([]byte, error) {
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(response, &decoded); err != nil {
+		return nil, err
+	}
+
+	decoded["id"], _ = json.Marshal(id)
+
+	return json.Marshal(decoded)
+}
+
+func compact(data []byte) json.RawMessage {
+	var buffer bytes.Buffer
+	if err := json.Compact(&buffer, data); err != nil {
+		return data
+	}
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,208 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+)
+
+// Endpoint - represents a node endpoint and its most recently observed health
+type Endpoint struct {
+	URL         string
+	ShardID     uint32
+	ChainID     uint64
+	BlockNumber uint64
+	Healthy     bool
+	Error       error
+	CheckedAt   time.Time
+	identified  bool
+}
+
+// HealthChecker - probes all known endpoints and keeps track of which ones are healthy
+type HealthChecker struct {
+	mutex       sync.RWMutex
+	urls        []string
+	endpoints   map[string]*Endpoint
+	maxBlockLag uint64
+	stop        chan struct{}
+}
+
+type nodeMetadata struct {
+	ShardID     uint32 `json:"shard-id"`
+	ChainConfig struct {
+		ChainID uint64 `json:"chain-id"`
+	} `json:"chain-config"`
+}
+
+// NewHealthChecker - creates a new health checker for a given set of endpoint urls
+func NewHealthChecker(urls []string, maxBlockLag uint64) *HealthChecker {
+	return &HealthChecker{
+		urls:        urls,
+		endpoints:   make(map[string]*Endpoint),
+		maxBlockLag: maxBlockLag,
+		stop:        make(chan struct{}),
+	}
+}
+
+// Check - probes every endpoint (chain id, shard id and block height) and updates their health
+// Endpoints are considered unhealthy if they can't be reached, report the wrong chain id or lag more than network.health.max_block_lag blocks behind the highest endpoint of the same shard
+func (checker *HealthChecker) Check() {
+	probed := make([]*Endpoint, len(checker.urls))
+
+	var waitGroup sync.WaitGroup
+	for i, url := range checker.urls {
+		waitGroup.Add(1)
+		go func(i int, url string) {
+			defer waitGroup.Done()
+			probed[i] = probe(url)
+		}(i, url)
+	}
+	waitGroup.Wait()
+
+	highest := make(map[uint32]uint64)
+	for _, endpoint := range probed {
+		if endpoint.Error == nil && endpoint.BlockNumber > highest[endpoint.ShardID] {
+			highest[endpoint.ShardID] = endpoint.BlockNumber
+		}
+	}
+
+	expectedChainID := uint64(0)
+	if config.Configuration.Network.API.ChainID != nil && config.Configuration.Network.API.ChainID.Value != nil {
+		expectedChainID = config.Configuration.Network.API.ChainID.Value.Uint64()
+	}
+
+	checker.mutex.Lock()
+	defer checker.mutex.Unlock()
+
+	for _, endpoint := range probed {
+		previous, seen := checker.endpoints[endpoint.URL]
+		if !endpoint.identified && seen {
+			// An endpoint that can't be reached can't tell us which shard it belongs to - keep the previously observed shard
+			endpoint.ShardID = previous.ShardID
+			endpoint.identified = previous.identified
+		}
+
+		switch {
+		case endpoint.Error != nil:
+		case expectedChainID > 0 && endpoint.ChainID > 0 && endpoint.ChainID != expectedChainID:
+			endpoint.Error = fmt.Errorf("chain id %d doesn't match the expected chain id %d", endpoint.ChainID, expectedChainID)
+		case highest[endpoint.ShardID]-endpoint.BlockNumber > checker.maxBlockLag:
+			endpoint.Error = fmt.Errorf("block height %d is more than %d blocks behind %d", endpoint.BlockNumber, checker.maxBlockLag, highest[endpoint.ShardID])
+		default:
+			endpoint.Healthy = true
+		}
+
+		if seen && previous.Healthy != endpoint.Healthy {
+			if endpoint.Healthy {
+				logger.Log(fmt.Sprintf("Endpoint %s (shard %d) is healthy again", endpoint.URL, endpoint.ShardID), config.Configuration.Framework.Verbose)
+			} else {
+				logger.WarningLog(fmt.Sprintf("Endpoint %s (shard %d) is unhealthy - error: %s", endpoint.URL, endpoint.ShardID, endpoint.Error.Error()), true)
+			}
+		}
+
+		checker.endpoints[endpoint.URL] = endpoint
+	}
+}
+
+// Start - re-checks all endpoints at the given interval until Stop is called
+func (checker *HealthChecker) Start(interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				checker.Check()
+			case <-checker.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop - stops the periodic health checks
+func (checker *HealthChecker) Stop() {
+	close(checker.stop)
+}
+
+// MarkUnhealthy - marks an endpoint as unhealthy until the next health check, e.g. after a connection error
+func (checker *HealthChecker) MarkUnhealthy(url string, err error) {
+	checker.mutex.Lock()
+	defer checker.mutex.Unlock()
+
+	if endpoint, ok := checker.endpoints[url]; ok {
+		if endpoint.Healthy {
+			logger.WarningLog(fmt.Sprintf("Endpoint %s (shard %d) is unhealthy - error: %s", url, endpoint.ShardID, err.Error()), true)
+		}
+		endpoint.Healthy = false
+		endpoint.Error = err
+	}
+}
+
+// Candidates - the identified endpoints of a given shard ordered by preference: healthy endpoints with the highest block height first, unhealthy endpoints last
+func (checker *HealthChecker) Candidates(shardID uint32) (endpoints []Endpoint) {
+	checker.mutex.RLock()
+	defer checker.mutex.RUnlock()
+
+	for _, url := range checker.urls {
+		if endpoint, ok := checker.endpoints[url]; ok && endpoint.identified && endpoint.ShardID == shardID {
+			endpoints = append(endpoints, *endpoint)
+		}
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].Healthy != endpoints[j].Healthy {
+			return endpoints[i].Healthy
+		}
+		return endpoints[i].BlockNumber > endpoints[j].BlockNumber
+	})
+
+	return endpoints
+}
+
+// Endpoints - all known endpoints
+func (checker *HealthChecker) Endpoints() (endpoints []Endpoint) {
+	checker.mutex.RLock()
+	defer checker.mutex.RUnlock()
+
+	for _, url := range checker.urls {
+		if endpoint, ok := checker.endpoints[url]; ok {
+			endpoints = append(endpoints, *endpoint)
+		}
+	}
+
+	return endpoints
+}
+
+func probe(url string) *Endpoint {
+	endpoint := &Endpoint{URL: url, CheckedAt: time.Now().UTC()}
+
+	result, err := Call(url, "hmy_getNodeMetadata")
+	if err != nil {
+		endpoint.Error = err
+		return endpoint
+	}
+
+	var metadata nodeMetadata
+	if err := json.Unmarshal(result, &metadata); err != nil {
+		endpoint.Error = err
+		return endpoint
+	}
+	endpoint.ShardID = metadata.ShardID
+	endpoint.ChainID = metadata.ChainConfig.ChainID
+	endpoint.identified = true
+
+	endpoint.BlockNumber, endpoint.Error = BlockNumber(url)
+
+	return endpoint
+}
@@ -0,0 +1,268 @@
+package network
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/metrics"
+)
+
+var errNoEndpoints = errors.New("no endpoints available")
+
+// TransactionNodes - keeps track of which node each transaction was sent through
+var TransactionNodes = &TransactionNodeRegistry{nodes: make(map[string]string)}
+
+// TransactionNodeRegistry - maps transaction hashes to the node they were sent through
+type TransactionNodeRegistry struct {
+	mutex sync.RWMutex
+	nodes map[string]string
+	order []string
+}
+
+// Record - records the node a given transaction was sent through
+func (registry *TransactionNodeRegistry) Record(txHash string, node string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if _, ok := registry.nodes[txHash]; !ok {
+		registry.order = append(registry.order, txHash)
+	}
+	registry.nodes[txHash] = node
+}
+
+// Node - returns the node a given transaction was sent through
+func (registry *TransactionNodeRegistry) Node(txHash string) string {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return registry.nodes[txHash]
+}
+
+// All - returns all recorded transaction hashes and nodes in the order they were sent
+func (registry *TransactionNodeRegistry) All() (hashes []string, nodes []string) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	for _, hash := range registry.order {
+		hashes = append(hashes, hash)
+		nodes = append(nodes, registry.nodes[hash])
+	}
+
+	return hashes, nodes
+}
+
+// Proxy - a local JSON-RPC proxy for a given shard which forwards requests to the healthiest endpoint of that shard
+// Requests are forwarded to the shard's original node if health checks are disabled and are answered from cassettes when replaying
+type Proxy struct {
+	ShardID  uint32
+	URL      string
+	node     string
+	checker  *HealthChecker
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewProxy - starts a new local proxy for a given shard - checker can be nil in which case all requests are forwarded to node
+func NewProxy(shardID uint32, node string, checker *HealthChecker) (*Proxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	proxy := &Proxy{
+		ShardID:  shardID,
+		URL:      fmt.Sprintf("http://%s", listener.Addr().String()),
+		node:     node,
+		checker:  checker,
+		listener: listener,
+	}
+	proxy.server = &http.Server{Handler: proxy}
+
+	go proxy.server.Serve(listener)
+
+	return proxy, nil
+}
+
+// Close - stops the proxy
+func (proxy *Proxy) Close() error {
+	return proxy.server.Close()
+}
+
+// ServeHTTP - forwards a JSON-RPC request to the healthiest endpoint, failing over to the next endpoint on connection errors or server errors
+func (proxy *Proxy) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	payload, err := ioutil.ReadAll(request.Body)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	status, body, node, err := proxy.forward(payload)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	recordTransaction(payload, body, node)
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+	writer.Write(body)
+}
+
+func (proxy *Proxy) forward(payload []byte) (status int, body []byte, node string, err error) {
+	if Recorder.Replaying() {
+		startedAt := time.Now()
+		body, err = Recorder.Replay(proxy.ShardID, payload)
+		if err == nil {
+			metrics.RPC.Observe(requestMethod(payload), "cassette", metrics.OutcomeReplayed, time.Since(startedAt))
+		}
+		return http.StatusOK, body, "cassette", err
+	}
+
+	err = errNoEndpoints
+
+	for i, url := range proxy.upstreams() {
+		if i > 0 {
+			logger.WarningLog(fmt.Sprintf("Failing over shard %d requests to endpoint %s - previous error: %s", proxy.ShardID, url, err.Error()), true)
+		}
+
+		status, body, err = post(url, payload)
+		if err == nil && status < http.StatusInternalServerError {
+			if Recorder.Recording() {
+				Recorder.Record(proxy.ShardID, url, payload, body)
+			}
+			return status, body, url, nil
+		}
+
+		if err == nil {
+			err = fmt.Errorf("%s responded with status %d", url, status)
+		}
+		// Throttling endpoints are still healthy - they're only skipped for this request
+		if _, throttled := err.(*RateLimitError); !throttled && proxy.checker != nil {
+			proxy.checker.MarkUnhealthy(url, err)
+		}
+	}
+
+	return 0, nil, "", err
+}
+
+func (proxy *Proxy) upstreams() (urls []string) {
+	if proxy.checker == nil {
+		return []string{proxy.node}
+	}
+
+	for _, endpoint := range proxy.checker.Candidates(proxy.ShardID) {
+		urls = append(urls, endpoint.URL)
+	}
+
+	return urls
+}
+
+// post - sends a JSON-RPC payload to a given endpoint - requests are rate limited per endpoint and throttled requests are retried using exponential backoff with jitter
+func post(url string, payload []byte) (status int, body []byte, err error) {
+	maxRetries := config.Configuration.Network.RateLimit.MaxRetries
+	method := requestMethod(payload)
+
+	for attempt := 0; ; attempt++ {
+		Limits.Wait(url)
+
+		var header http.Header
+		startedAt := time.Now()
+		status, header, body, err = send(url, payload)
+		if err != nil {
+			metrics.RPC.Observe(method, url, metrics.OutcomeTransportError, time.Since(startedAt))
+			return 0, nil, err
+		}
+
+		throttled, retryAfter := rateLimited(status, header, body)
+		metrics.RPC.Observe(method, url, outcome(status, body, throttled), time.Since(startedAt))
+
+		if !throttled || !config.Configuration.Network.RateLimit.Enabled {
+			Limits.Succeeded(url)
+			return status, body, nil
+		}
+
+		Limits.Throttled(url, retryAfter)
+		if attempt >= maxRetries {
+			return status, body, &RateLimitError{URL: url, Status: status}
+		}
+
+		wait := Backoff(attempt)
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		logger.WarningLog(fmt.Sprintf("Endpoint %s throttled the request (status %d) - retrying in %s (attempt %d/%d)", url, status, wait, attempt+1, maxRetries), config.Configuration.Framework.Verbose)
+		metrics.Sleep("rate limit backoff", wait)
+	}
+}
+
+func send(url string, payload []byte) (int, http.Header, []byte, error) {
+	response, err := httpClient.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	return response.StatusCode, response.Header, body, nil
+}
+
+// outcome - classifies a response for the RPC metrics
+func outcome(status int, body []byte, throttled bool) string {
+	switch {
+	case throttled:
+		return metrics.OutcomeThrottled
+	case status < http.StatusOK || status >= http.StatusMultipleChoices:
+		return fmt.Sprintf("http %d", status)
+	}
+
+	var response rpcResponse
+	if err := json.Unmarshal(body, &response); err == nil && response.Error != nil {
+		return metrics.OutcomeRPCError
+	}
+
+	return metrics.OutcomeOK
+}
+
+func requestMethod(payload []byte) string {
+	var request rpcRequest
+	if err := json.Unmarshal(payload, &request); err != nil || request.Method == "" {
+		return "unknown"
+	}
+
+	return request.Method
+}
+
+func recordTransaction(payload []byte, body []byte, node string) {
+	var request rpcRequest
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return
+	}
+
+	switch request.Method {
+	case "hmy_sendRawTransaction", "hmy_sendRawStakingTransaction":
+		var response rpcResponse
+		if err := json.Unmarshal(body, &response); err != nil || response.Error != nil {
+			return
+		}
+
+		var txHash string
+		if err := json.Unmarshal(response.Result, &txHash); err == nil && txHash != "" {
+			TransactionNodes.Record(txHash, node)
+			logger.TransactionLog(fmt.Sprintf("Transaction %s was sent through node %s", txHash, node), config.Configuration.Framework.Verbose)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/metrics"
+)
+
+var (
+	// Limits - the per endpoint rate limiters shared by all RPC calls
+	Limits = &RateLimiter{buckets: make(map[string]*bucket)}
+
+	// Senders - limits how many transactions can be sent concurrently
+	Senders = NewConcurrency()
+
+	rateLimitRegex = regexp.MustCompile(`(?i)(rate.?limit|too many requests|limit exceeded|exceeded the rate|throttl)`)
+)
+
+// rateLimitCode - the JSON-RPC error code commonly used by public endpoints when throttling requests
+const rateLimitCode = -32005
+
+// RateLimitError - returned when an endpoint kept throttling a request after all retries
+type RateLimitError struct {
+	URL    string
+	Status int
+}
+
+func (err *RateLimitError) Error() string {
+	return fmt.Sprintf("%s is rate limiting requests (status %d)", err.URL, err.Status)
+}
+
+// RateLimiter - token buckets per endpoint using additive increase/multiplicative decrease of the request rate
+type RateLimiter struct {
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	rate         float64
+	tokens       float64
+	updatedAt    time.Time
+	blockedUntil time.Time
+}
+
+// Wait - blocks until a request can be sent to a given endpoint
+func (limiter *RateLimiter) Wait(url string) {
+	if !config.Configuration.Network.RateLimit.Enabled {
+		return
+	}
+
+	for {
+		limiter.mutex.Lock()
+		bucket := limiter.bucket(url)
+		now := time.Now()
+		bucket.refill(now)
+
+		var wait time.Duration
+		switch {
+		case now.Before(bucket.blockedUntil):
+			wait = bucket.blockedUntil.Sub(now)
+		case bucket.tokens >= 1:
+			bucket.tokens--
+		default:
+			wait = time.Duration((1 - bucket.tokens) / bucket.rate * float64(time.Second))
+		}
+		limiter.mutex.Unlock()
+
+		if wait <= 0 {
+			return
+		}
+		metrics.Sleep("rate limit", wait)
+	}
+}
+
+// Throttled - halves the request rate of a given endpoint and pauses requests to it for retryAfter (if set)
+func (limiter *RateLimiter) Throttled(url string, retryAfter time.Duration) {
+	settings := config.Configuration.Network.RateLimit
+
+	limiter.mutex.Lock()
+	bucket := limiter.bucket(url)
+	previousRate := bucket.rate
+	bucket.rate = math.Max(settings.MinRequestsPerSecond, bucket.rate/2)
+	bucket.tokens = 0
+	if retryAfter > 0 {
+		bucket.blockedUntil = time.Now().Add(retryAfter)
+	}
+	rate := bucket.rate
+	limiter.mutex.Unlock()
+
+	Senders.throttled()
+
+	if rate < previousRate {
+		logger.WarningLog(fmt.Sprintf("Endpoint %s is throttling requests - lowering the request rate to %.2f requests/second and the transaction concurrency to %d", url, rate, Senders.Limit()), config.Configuration.Framework.Verbose)
+	}
+}
+
+// Succeeded - gradually raises the request rate of a given endpoint back to the configured rate
+func (limiter *RateLimiter) Succeeded(url string) {
+	settings := config.Configuration.Network.RateLimit
+	if !settings.Enabled {
+		return
+	}
+
+	limiter.mutex.Lock()
+	bucket := limiter.bucket(url)
+	if bucket.rate < settings.RequestsPerSecond {
+		bucket.rate = math.Min(settings.RequestsPerSecond, bucket.rate+settings.RequestsPerSecond/100)
+	}
+	limiter.mutex.Unlock()
+
+	Senders.succeeded()
+}
+
+// Rate - the current request rate (requests/second) of a given endpoint
+func (limiter *RateLimiter) Rate(url string) float64 {
+	limiter.mutex.Lock()
+	defer limiter.mutex.Unlock()
+
+	return limiter.bucket(url).rate
+}
+
+func (limiter *RateLimiter) bucket(url string) *bucket {
+	existing, ok := limiter.buckets[url]
+	if !ok {
+		settings := config.Configuration.Network.RateLimit
+		existing = &bucket{rate: settings.RequestsPerSecond, tokens: float64(settings.Burst), updatedAt: time.Now()}
+		limiter.buckets[url] = existing
+	}
+
+	return existing
+}
+
+func (bucket *bucket) refill(now time.Time) {
+	bucket.tokens = math.Min(float64(config.Configuration.Network.RateLimit.Burst), bucket.tokens+now.Sub(bucket.updatedAt).Seconds()*bucket.rate)
+	bucket.updatedAt = now
+}
+
+// Concurrency - a semaphore whose limit is halved whenever an endpoint throttles requests and recovers as requests succeed again
+type Concurrency struct {
+	mutex     sync.Mutex
+	cond      *sync.Cond
+	active    int
+	limit     int
+	successes int
+}
+
+// NewConcurrency - creates a new adaptive semaphore
+func NewConcurrency() *Concurrency {
+	concurrency := &Concurrency{}
+	concurrency.cond = sync.NewCond(&concurrency.mutex)
+	return concurrency
+}
+
+// Acquire - blocks until a slot is available - does nothing if rate limiting is disabled
+func (concurrency *Concurrency) Acquire() {
+	if !config.Configuration.Network.RateLimit.Enabled {
+		return
+	}
+
+	concurrency.mutex.Lock()
+	defer concurrency.mutex.Unlock()
+
+	for concurrency.active >= concurrency.currentLimit() {
+		concurrency.cond.Wait()
+	}
+	concurrency.active++
+}
+
+// Release - releases a slot acquired using Acquire
+func (concurrency *Concurrency) Release() {
+	if !config.Configuration.Network.RateLimit.Enabled {
+		return
+	}
+
+	concurrency.mutex.Lock()
+	defer concurrency.mutex.Unlock()
+
+	if concurrency.active > 0 {
+		concurrency.active--
+	}
+	concurrency.cond.Broadcast()
+}
+
+// Limit - the current concurrency limit
+func (concurrency *Concurrency) Limit() int {
+	concurrency.mutex.Lock()
+	defer concurrency.mutex.Unlock()
+
+	return concurrency.currentLimit()
+}
+
+func (concurrency *Concurrency) currentLimit() int {
+	if concurrency.limit <= 0 {
+		concurrency.limit = config.Configuration.Network.RateLimit.Concurrency
+	}
+
+	return concurrency.limit
+}
+
+func (concurrency *Concurrency) throttled() {
+	concurrency.mutex.Lock()
+	defer concurrency.mutex.Unlock()
+
+	if limit := concurrency.currentLimit() / 2; limit >= 1 {
+		concurrency.limit = limit
+	}
+	concurrency.successes = 0
+}
+
+// succeeded - raises the limit by one for every limit * 10 successful requests
+func (concurrency *Concurrency) succeeded() {
+	concurrency.mutex.Lock()
+	defer concurrency.mutex.Unlock()
+
+	limit := concurrency.currentLimit()
+	if limit >= config.Configuration.Network.RateLimit.Concurrency {
+		return
+	}
+
+	concurrency.successes++
+	if concurrency.successes >= limit*10 {
+		concurrency.limit++
+		concurrency.successes = 0
+		concurrency.cond.Broadcast()
+	}
+}
+
+// Backoff - exponential backoff with jitter for a given (zero based) retry attempt
+func Backoff(attempt int) time.Duration {
+	settings := config.Configuration.Network.RateLimit.Backoff
+	base := time.Duration(settings.Base) * time.Millisecond
+	max := time.Duration(settings.Max) * time.Millisecond
+
+	ceiling := max
+	if attempt < 30 && base<<uint(attempt) < max {
+		ceiling = base << uint(attempt)
+	}
+
+	// Equal jitter - wait at least half of the ceiling so that retries never hammer the endpoint
+	half := int64(ceiling / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// rateLimited - whether or not a response signals that the endpoint is throttling requests, and for how long it asked to back off
+func rateLimited(status int, header http.Header, body []byte) (bool, time.Duration) {
+	retryAfter := time.Duration(0)
+	if header != nil {
+		if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+
+	if status == http.StatusTooManyRequests {
+		return true, retryAfter
+	}
+
+	// A node that is down also answers with 503 - it's only treated as throttling if it says so, otherwise the request fails over to another endpoint right away
+	if status == http.StatusServiceUnavailable && header != nil && header.Get("Retry-After") != "" {
+		return true, retryAfter
+	}
+
+	if len(body) == 0 {
+		return false, 0
+	}
+
+	var response rpcResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		// Gateways in front of the nodes answer with plain text or html when throttling
+		return status >= http.StatusBadRequest && rateLimitRegex.Match(body), retryAfter
+	}
+
+	if response.Error != nil && (response.Error.Code == rateLimitCode || rateLimitRegex.MatchString(response.Error.Message)) {
+		return true, retryAfter
+	}
+
+	return false, 0
+}
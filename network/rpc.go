@@ -0,0 +1,127 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var requestID int64
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      interface{}   `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Call - performs a JSON-RPC call against a given node
+func Call(node string, method string, params ...interface{}) (json.RawMessage, error) {
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	payload, err := json.Marshal(rpcRequest{JSONRPC: "2.0", ID: atomic.AddInt64(&requestID, 1), Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+
+	status, body, err := post(node, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if status < 200 || status > 299 {
+		return nil, fmt.Errorf("%s responded with status %d", node, status)
+	}
+
+	var decoded rpcResponse
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, err
+	}
+
+	if decoded.Error != nil {
+		return nil, fmt.Errorf("%s: %s", method, decoded.Error.Message)
+	}
+
+	return decoded.Result, nil
+}
+
+// BlockNumber - retrieves the current block number of a given node
+func BlockNumber(node string) (uint64, error) {
+	result, err := Call(node, "hmy_blockNumber")
+	if err != nil {
+		return 0, err
+	}
+
+	return ParseQuantity(result)
+}
+
+// Epoch - retrieves the current epoch of a given node
+func Epoch(node string) (uint64, error) {
+	result, err := Call(node, "hmy_getEpoch")
+	if err != nil {
+		return 0, err
+	}
+
+	return ParseQuantity(result)
+}
+
+// TransactionBlockNumber - retrieves the number of the block a given (staking) transaction was included in - found is false while the transaction is pending
+func TransactionBlockNumber(node string, txHash string) (blockNumber uint64, found bool, err error) {
+	result, err := Call(node, "hmy_getTransactionReceipt", txHash)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var receipt map[string]json.RawMessage
+	if err := json.Unmarshal(result, &receipt); err != nil || receipt == nil {
+		return 0, false, err
+	}
+
+	raw, ok := receipt["blockNumber"]
+	if !ok {
+		return 0, false, nil
+	}
+
+	blockNumber, err = ParseQuantity(raw)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return blockNumber, true, nil
+}
+
+// ParseQuantity - parses both hex encoded (0x...) and plain numeric JSON-RPC quantities
+func ParseQuantity(raw json.RawMessage) (uint64, error) {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return 0, err
+	}
+
+	switch typed := value.(type) {
+	case string:
+		if strings.HasPrefix(typed, "0x") {
+			return strconv.ParseUint(strings.TrimPrefix(typed, "0x"), 16, 64)
+		}
+		return strconv.ParseUint(typed, 10, 64)
+	case float64:
+		return uint64(typed), nil
+	}
+
+	return 0, fmt.Errorf("unsupported quantity %s", string(raw))
+}
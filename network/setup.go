@@ -0,0 +1,154 @@
+package network
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	sdkNetworkTypes "github.com/harmony-one/go-lib/network/types/network"
+	goSDK_RPC "github.com/harmony-one/go-sdk/pkg/rpc"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+)
+
+// Health - the active health checker (nil if health checks are disabled)
+var Health *HealthChecker
+
+var proxies []*Proxy
+
+// Setup - routes all shard RPC traffic through local proxies which measure, rate limit and (if enabled) record or replay every call
+// Endpoints are probed at startup when health checks are enabled, the proxies then forward requests to the healthiest endpoint of each shard
+func Setup() error {
+	if len(proxies) > 0 {
+		return nil
+	}
+
+	healthChecks := config.Configuration.Network.Health.Enabled && !Recorder.Replaying()
+
+	var checker *HealthChecker
+	var urls []string
+	if healthChecks {
+		urls = candidateEndpoints()
+		checker = NewHealthChecker(urls, config.Configuration.Network.Health.MaxBlockLag)
+		checker.Check()
+
+		for _, endpoint := range checker.Endpoints() {
+			if endpoint.Healthy {
+				logger.Log(fmt.Sprintf("Endpoint %s (shard %d) is healthy - block height: %d", endpoint.URL, endpoint.ShardID, endpoint.BlockNumber), config.Configuration.Framework.Verbose)
+			} else {
+				logger.WarningLog(fmt.Sprintf("Endpoint %s is unhealthy - error: %s", endpoint.URL, endpoint.Error.Error()), true)
+			}
+		}
+	}
+
+	shards := make(map[uint32]sdkNetworkTypes.Shard)
+	for shardID := uint32(0); shardID < uint32(config.Configuration.Network.Shards); shardID++ {
+		if checker != nil && len(checker.Candidates(shardID)) == 0 {
+			closeProxies()
+			return fmt.Errorf("failed to find any endpoints for shard %d - probed endpoints: %s", shardID, strings.Join(urls, ", "))
+		}
+
+		proxy, err := NewProxy(shardID, config.Configuration.Network.API.Shards[shardID].Node, checker)
+		if err != nil {
+			closeProxies()
+			return err
+		}
+		proxies = append(proxies, proxy)
+
+		shards[shardID] = sdkNetworkTypes.Shard{
+			Node:      proxy.URL,
+			RPCClient: goSDK_RPC.NewHTTPHandler(proxy.URL),
+		}
+	}
+
+	if Recorder.Recording() {
+		if err := SaveShardingStructure(); err != nil {
+			closeProxies()
+			return err
+		}
+	}
+
+	if err := Recorder.SetTestCase(""); err != nil {
+		closeProxies()
+		return err
+	}
+
+	config.Configuration.Network.API.Shards = shards
+	if checker != nil {
+		checker.Start(interval())
+		Health = checker
+	}
+
+	return nil
+}
+
+// Stop - stops the periodic health checks and all local proxies and saves the current cassette if recording
+func Stop() error {
+	if Health != nil {
+		Health.Stop()
+		Health = nil
+	}
+	closeProxies()
+
+	return Recorder.Close()
+}
+
+// ShardEndpoint - the endpoint RPC calls for a given shard are currently forwarded to
+func ShardEndpoint(shardID uint32) string {
+	for _, proxy := range proxies {
+		if proxy.ShardID == shardID {
+			if upstreams := proxy.upstreams(); len(upstreams) > 0 {
+				return upstreams[0]
+			}
+		}
+	}
+
+	if shard, ok := config.Configuration.Network.API.Shards[shardID]; ok {
+		return shard.Node
+	}
+
+	return ""
+}
+
+func closeProxies() {
+	for _, proxy := range proxies {
+		proxy.Close()
+	}
+	proxies = nil
+}
+
+// candidateEndpoints - all endpoints known for the current network: the configured endpoints, the resolved shard nodes and the nodes listed in the sharding structure
+func candidateEndpoints() (urls []string) {
+	var candidates []string
+	// Explicitly specified nodes (e.g. a local or mock node) shouldn't fail over to the configured endpoints of the network
+	if len(config.Args.Nodes) == 0 {
+		candidates = append(candidates, config.Configuration.Network.Endpoints[config.Configuration.Network.Name]...)
+	}
+	candidates = append(candidates, config.Args.Nodes...)
+	candidates = append(candidates, config.Configuration.Network.Nodes...)
+
+	for shardID := uint32(0); shardID < uint32(config.Configuration.Network.Shards); shardID++ {
+		if shard, ok := config.Configuration.Network.API.Shards[shardID]; ok {
+			candidates = append(candidates, shard.Node)
+		}
+	}
+
+	for _, route := range config.Configuration.Network.API.ShardingStructure {
+		candidates = append(candidates, route.HTTP)
+	}
+
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		candidate = strings.TrimSuffix(strings.TrimSpace(candidate), "/")
+		if candidate != "" && !seen[candidate] {
+			seen[candidate] = true
+			urls = append(urls, candidate)
+		}
+	}
+
+	return urls
+}
+
+func interval() time.Duration {
+	return time.Duration(config.Configuration.Network.Health.Interval) * time.Second
+}
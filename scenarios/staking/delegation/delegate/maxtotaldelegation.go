@@ -0,0 +1,104 @@
+package delegate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/staking"
+	"github.com/harmony-one/harmony-tf/testing"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+// MaxTotalDelegationScenario - executes a delegation test case that delegates exactly up to the validator's maximum total delegation (or one unit past it using the mode exceed_max_total_delegation)
+func MaxTotalDelegationScenario(testCase *testing.TestCase) {
+	testing.Title(testCase, "header", testCase.Verbose)
+	testCase.Executed = true
+	testCase.StartedAt = time.Now().UTC()
+
+	if testCase.ErrorOccurred(nil) {
+		return
+	}
+
+	// The validator will end up at its maximum total delegation - never use a shared validator for this scenario
+	testCase.StakingParameters.ReuseExistingValidator = false
+
+	// The self delegation + the delegation will at most add up to the maximum total delegation
+	requiredFunding := testCase.StakingParameters.Create.Validator.MaximumTotalDelegation
+	fundingMultiple := int64(1)
+	_, _, err := funding.CalculateFundingDetails(requiredFunding, fundingMultiple, 0)
+	if testCase.ErrorOccurred(err) {
+		return
+	}
+
+	validatorName := accounts.GenerateTestCaseAccountName(testCase.Name, "Validator")
+	account, validator, err := staking.ReuseOrCreateValidator(testCase, validatorName)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to create validator using account %s", validatorName)
+		testCase.HandleError(err, account, msg)
+		return
+	}
+
+	if validator.Exists {
+		validatorInfo, err := staking.ValidatorInformation(testCase.StakingParameters.FromShardID, validator.Account.Address)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to retrieve validator info for validator %s", validator.Account.Address)
+			testCase.HandleError(err, validator.Account, msg)
+			return
+		}
+
+		startingTotalDelegation := staking.TotalDelegation(validatorInfo)
+		delegationAmount := staking.RemainingDelegationCapacity(validatorInfo)
+		switch testCase.StakingParameters.Mode {
+		case "exceed_max_total_delegation", "exceedmaxtotaldelegation":
+			delegationAmount = delegationAmount.Add(numeric.SmallestDec())
+		}
+		testCase.StakingParameters.Delegation.Delegate.Amount = delegationAmount
+
+		logger.StakingLog(fmt.Sprintf("Validator %s has a total delegation of %f and a maximum total delegation of %f - will delegate %f", validator.Account.Address, startingTotalDelegation, validatorInfo.Validator.MaxTotalDelegation, delegationAmount), testCase.Verbose)
+
+		delegatorName := accounts.GenerateTestCaseAccountName(testCase.Name, "Delegator")
+		delegatorAccount, err := testing.GenerateAndFundAccount(testCase, delegatorName, delegationAmount, fundingMultiple)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to generate and fund account %s", delegatorName)
+			testCase.HandleError(err, &delegatorAccount, msg)
+			return
+		}
+
+		delegationTx, delegationSucceeded, err := staking.BasicDelegation(testCase, &delegatorAccount, validator.Account, nil)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to delegate from account %s, address %s to validator %s, address: %s", delegatorAccount.Name, delegatorAccount.Address, validator.Account.Name, validator.Account.Address)
+			testCase.HandleError(err, validator.Account, msg)
+			return
+		}
+		testCase.Transactions = append(testCase.Transactions, delegationTx)
+
+		endingValidatorInfo, err := staking.ValidatorInformation(testCase.StakingParameters.FromShardID, validator.Account.Address)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to retrieve validator info for validator %s", validator.Account.Address)
+			testCase.HandleError(err, validator.Account, msg)
+			return
+		}
+
+		endingTotalDelegation := staking.TotalDelegation(endingValidatorInfo)
+		expectedTotalDelegation := startingTotalDelegation.Add(delegationAmount)
+		logger.StakingLog(fmt.Sprintf("Validator %s has an ending total delegation of %f - expected total delegation if the delegation succeeded: %f", validator.Account.Address, endingTotalDelegation, expectedTotalDelegation), testCase.Verbose)
+
+		testCase.Result = delegationTx.Success && delegationSucceeded && endingTotalDelegation.Equal(expectedTotalDelegation)
+
+		logger.TeardownLog("Performing test teardown (returning funds and removing accounts)", testCase.Verbose)
+		testing.Teardown(&delegatorAccount, config.Configuration.Funding.Account.Address)
+	}
+
+	if !testCase.StakingParameters.ReuseExistingValidator {
+		testing.Teardown(validator.Account, config.Configuration.Funding.Account.Address)
+	}
+
+	logger.ResultLog(testCase.Result, testCase.Expected, testCase.Verbose)
+	testing.Title(testCase, "footer", testCase.Verbose)
+
+	testCase.FinishedAt = time.Now().UTC()
+}
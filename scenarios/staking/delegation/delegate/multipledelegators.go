@@ -0,0 +1,163 @@
+package delegate
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/staking"
+	"github.com/harmony-one/harmony-tf/testing"
+	"github.com/harmony-one/harmony/numeric"
+
+	sdkAccounts "github.com/harmony-one/go-lib/accounts"
+	sdkValidator "github.com/harmony-one/go-lib/staking/validator"
+	sdkTxs "github.com/harmony-one/go-lib/transactions"
+)
+
+// MultipleDelegatorsScenario - executes a delegation test case where multiple delegators delegate to the same validator at the same time
+func MultipleDelegatorsScenario(testCase *testing.TestCase) {
+	testing.Title(testCase, "header", testCase.Verbose)
+	testCase.Executed = true
+	testCase.StartedAt = time.Now().UTC()
+
+	if testCase.ErrorOccurred(nil) {
+		return
+	}
+
+	delegatorCount := testCase.StakingParameters.Delegation.DelegatorCount
+	delegationAmount := testCase.StakingParameters.Delegation.Delegate.Amount
+	totalDelegationAmount := delegationAmount.Mul(numeric.NewDec(delegatorCount))
+	requiredFunding := testCase.StakingParameters.Create.Validator.Amount.Add(totalDelegationAmount)
+	_, _, err := funding.CalculateFundingDetails(requiredFunding, 1, 0)
+	if testCase.ErrorOccurred(err) {
+		return
+	}
+
+	validatorName := accounts.GenerateTestCaseAccountName(testCase.Name, "Validator")
+	account, validator, err := staking.ReuseOrCreateValidator(testCase, validatorName)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to create validator using account %s", validatorName)
+		testCase.HandleError(err, account, msg)
+		return
+	}
+
+	if validator.Exists {
+		validatorInfo, err := staking.ValidatorInformation(testCase.StakingParameters.FromShardID, validator.Account.Address)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to retrieve validator info for validator %s", validator.Account.Address)
+			testCase.HandleError(err, validator.Account, msg)
+			return
+		}
+		startingTotalDelegation := staking.TotalDelegation(validatorInfo)
+
+		nameTemplate := accounts.GenerateTestCaseAccountName(testCase.Name, "Delegator_")
+		delegatorAccounts, err := funding.GenerateAndFundAccounts(delegatorCount, nameTemplate, delegationAmount, testCase.StakingParameters.FromShardID, testCase.StakingParameters.FromShardID)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to generate a total of %d delegator accounts", delegatorCount)
+			testCase.HandleError(err, validator.Account, msg)
+			return
+		}
+
+		executeMultipleDelegations(testCase, delegatorAccounts, validator)
+		txsSuccessful := (testCase.SuccessfulTxCount == delegatorCount)
+		logger.TransactionLog(fmt.Sprintf("A total of %d/%d delegation transactions were successful", testCase.SuccessfulTxCount, delegatorCount), testCase.Verbose)
+
+		endingValidatorInfo, err := staking.ValidatorInformation(testCase.StakingParameters.FromShardID, validator.Account.Address)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to retrieve validator info for validator %s", validator.Account.Address)
+			testCase.HandleError(err, validator.Account, msg)
+			multipleDelegatorsTeardown(testCase, delegatorAccounts)
+			return
+		}
+
+		delegationsRecorded := verifyRecordedDelegations(testCase, endingValidatorInfo, delegatorAccounts, delegationAmount)
+
+		endingTotalDelegation := staking.TotalDelegation(endingValidatorInfo)
+		expectedTotalDelegation := startingTotalDelegation.Add(totalDelegationAmount)
+		logger.StakingLog(fmt.Sprintf("Validator %s has an ending total delegation of %f - expected total delegation: %f", validator.Account.Address, endingTotalDelegation, expectedTotalDelegation), testCase.Verbose)
+
+		testCase.Result = txsSuccessful && int64(len(delegatorAccounts)) == delegatorCount && delegationsRecorded && endingTotalDelegation.Equal(expectedTotalDelegation)
+
+		logger.TeardownLog("Performing test teardown (returning funds and removing accounts)", testCase.Verbose)
+		multipleDelegatorsTeardown(testCase, delegatorAccounts)
+	}
+
+	if !testCase.StakingParameters.ReuseExistingValidator {
+		testing.Teardown(validator.Account, config.Configuration.Funding.Account.Address)
+	}
+
+	logger.ResultLog(testCase.Result, testCase.Expected, testCase.Verbose)
+	testing.Title(testCase, "footer", testCase.Verbose)
+
+	testCase.FinishedAt = time.Now().UTC()
+}
+
+func executeMultipleDelegations(testCase *testing.TestCase, delegatorAccounts []sdkAccounts.Account, validator *sdkValidator.Validator) {
+	txs := make(chan sdkTxs.Transaction, len(delegatorAccounts))
+	var waitGroup sync.WaitGroup
+
+	for _, delegatorAccount := range delegatorAccounts {
+		waitGroup.Add(1)
+		go executeDelegation(testCase, delegatorAccount, validator, txs, &waitGroup)
+	}
+
+	waitGroup.Wait()
+	close(txs)
+
+	testCase.SuccessfulTxCount = 0
+	for tx := range txs {
+		testCase.Transactions = append(testCase.Transactions, tx)
+		if tx.Success {
+			testCase.SuccessfulTxCount++
+		}
+	}
+}
+
+func executeDelegation(testCase *testing.TestCase, delegatorAccount sdkAccounts.Account, validator *sdkValidator.Validator, responses chan<- sdkTxs.Transaction, waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
+
+	tx, _, err := staking.BasicDelegation(testCase, &delegatorAccount, validator.Account, nil)
+	if err != nil {
+		logger.ErrorLog(fmt.Sprintf("Failed to delegate from account %s, address %s to validator %s - error: %s", delegatorAccount.Name, delegatorAccount.Address, validator.Account.Address, err.Error()), testCase.Verbose)
+		tx = sdkTxs.Transaction{
+			FromAddress: delegatorAccount.Address,
+			ToAddress:   validator.Account.Address,
+			Success:     false,
+			Error:       err,
+		}
+	}
+
+	responses <- tx
+}
+
+func verifyRecordedDelegations(testCase *testing.TestCase, validatorInfo sdkValidator.RPCValidatorResult, delegatorAccounts []sdkAccounts.Account, expectedAmount numeric.Dec) bool {
+	recordedCount := 0
+
+	for _, delegatorAccount := range delegatorAccounts {
+		delegatedAmount := staking.DelegatedAmount(validatorInfo, delegatorAccount.Address)
+		if delegatedAmount.Equal(expectedAmount) {
+			recordedCount++
+		} else {
+			logger.StakingLog(fmt.Sprintf("Delegator %s has a recorded delegation of %f to validator %s - expected delegation: %f", delegatorAccount.Address, delegatedAmount, validatorInfo.Validator.Address, expectedAmount), testCase.Verbose)
+		}
+	}
+
+	logger.StakingLog(fmt.Sprintf("A total of %d/%d delegations were recorded with the expected amount %f", recordedCount, len(delegatorAccounts), expectedAmount), testCase.Verbose)
+
+	return recordedCount == len(delegatorAccounts)
+}
+
+func multipleDelegatorsTeardown(testCase *testing.TestCase, delegatorAccounts []sdkAccounts.Account) {
+	var waitGroup sync.WaitGroup
+	waitGroup.Add(len(delegatorAccounts))
+
+	for i := range delegatorAccounts {
+		go testing.AsyncTeardown(&delegatorAccounts[i], config.Configuration.Funding.Account.Address, &waitGroup)
+	}
+
+	waitGroup.Wait()
+}
@@ -57,11 +57,11 @@ func StandardScenario(testCase *testing.TestCase) {
 		testCase.Result = delegationTx.Success && delegationSucceeded
 
 		logger.TeardownLog("Performing test teardown (returning funds and removing accounts)", testCase.Verbose)
-		testing.Teardown(&delegatorAccount, testCase.StakingParameters.FromShardID, config.Configuration.Funding.Account.Address, testCase.StakingParameters.FromShardID)
+		testing.Teardown(&delegatorAccount, config.Configuration.Funding.Account.Address)
 	}
 
 	if !testCase.StakingParameters.ReuseExistingValidator {
-		testing.Teardown(validator.Account, testCase.StakingParameters.FromShardID, config.Configuration.Funding.Account.Address, testCase.StakingParameters.FromShardID)
+		testing.Teardown(validator.Account, config.Configuration.Funding.Account.Address)
 	}
 
 	logger.ResultLog(testCase.Result, testCase.Expected, testCase.Verbose)
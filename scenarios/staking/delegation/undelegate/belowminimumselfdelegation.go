@@ -0,0 +1,96 @@
+package undelegate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/staking"
+	"github.com/harmony-one/harmony-tf/testing"
+	"github.com/harmony-one/harmony/numeric"
+	"github.com/harmony-one/harmony/staking/effective"
+)
+
+// BelowMinimumSelfDelegationScenario - executes an undelegation test case where a validator undelegates its own stake below its minimum self delegation
+func BelowMinimumSelfDelegationScenario(testCase *testing.TestCase) {
+	testing.Title(testCase, "header", testCase.Verbose)
+	testCase.Executed = true
+	testCase.StartedAt = time.Now().UTC()
+
+	if testCase.ErrorOccurred(nil) {
+		return
+	}
+
+	// The validator will end up inactive - never use a shared validator for this scenario
+	testCase.StakingParameters.ReuseExistingValidator = false
+
+	fundingMultiple := int64(1)
+	_, _, err := funding.CalculateFundingDetails(testCase.StakingParameters.Create.Validator.Amount, fundingMultiple, 0)
+	if testCase.ErrorOccurred(err) {
+		return
+	}
+
+	validatorName := accounts.GenerateTestCaseAccountName(testCase.Name, "Validator")
+	account, validator, err := staking.ReuseOrCreateValidator(testCase, validatorName)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to create validator using account %s", validatorName)
+		testCase.HandleError(err, account, msg)
+		return
+	}
+
+	if validator.Exists {
+		validatorInfo, err := staking.ValidatorInformation(testCase.StakingParameters.FromShardID, validator.Account.Address)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to retrieve validator info for validator %s", validator.Account.Address)
+			testCase.HandleError(err, validator.Account, msg)
+			return
+		}
+
+		startingSelfDelegation := staking.SelfDelegation(validatorInfo)
+		minimumSelfDelegation := validatorInfo.Validator.MinSelfDelegation
+
+		// Unless an explicit amount has been configured, undelegate exactly one unit more than what keeps the validator at its minimum self delegation
+		if testCase.StakingParameters.Delegation.Undelegate.RawAmount == "" {
+			testCase.StakingParameters.Delegation.Undelegate.Amount = startingSelfDelegation.Sub(minimumSelfDelegation).Add(numeric.SmallestDec())
+		}
+		undelegationAmount := testCase.StakingParameters.Delegation.Undelegate.Amount
+
+		logger.StakingLog(fmt.Sprintf("Validator %s has a self delegation of %f and a minimum self delegation of %f - will undelegate %f", validator.Account.Address, startingSelfDelegation, minimumSelfDelegation, undelegationAmount), testCase.Verbose)
+
+		undelegationTx, undelegationSucceeded, err := staking.BasicUndelegation(testCase, validator.Account, validator.Account, nil)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to undelegate the self stake of validator %s, address: %s", validator.Account.Name, validator.Account.Address)
+			testCase.HandleError(err, validator.Account, msg)
+			return
+		}
+		testCase.Transactions = append(testCase.Transactions, undelegationTx)
+
+		endingValidatorInfo, err := staking.ValidatorInformation(testCase.StakingParameters.FromShardID, validator.Account.Address)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to retrieve validator info for validator %s", validator.Account.Address)
+			testCase.HandleError(err, validator.Account, msg)
+			return
+		}
+
+		endingSelfDelegation := staking.SelfDelegation(endingValidatorInfo)
+		expectedSelfDelegation := startingSelfDelegation.Sub(undelegationAmount)
+		eligibilityStatus := endingValidatorInfo.Validator.EligibilityStatus
+		expectedEligibilityStatus := effective.Inactive.String()
+
+		logger.StakingLog(fmt.Sprintf("Validator %s has an ending self delegation of %f - expected self delegation: %f", validator.Account.Address, endingSelfDelegation, expectedSelfDelegation), testCase.Verbose)
+		logger.StakingLog(fmt.Sprintf("Validator %s has the eligibility status %s - expected eligibility status: %s", validator.Account.Address, eligibilityStatus, expectedEligibilityStatus), testCase.Verbose)
+
+		testCase.Result = undelegationTx.Success && undelegationSucceeded && endingSelfDelegation.Equal(expectedSelfDelegation) && eligibilityStatus == expectedEligibilityStatus
+	}
+
+	logger.TeardownLog("Performing test teardown (returning funds and removing accounts)", testCase.Verbose)
+	testing.Teardown(validator.Account, config.Configuration.Funding.Account.Address)
+
+	logger.ResultLog(testCase.Result, testCase.Expected, testCase.Verbose)
+	testing.Title(testCase, "footer", testCase.Verbose)
+
+	testCase.FinishedAt = time.Now().UTC()
+}
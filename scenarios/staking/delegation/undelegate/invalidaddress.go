@@ -60,9 +60,7 @@ func InvalidAddressScenario(testCase *testing.TestCase) {
 	}
 	testCase.Transactions = append(testCase.Transactions, tx)
 
-	if config.Configuration.Network.StakingWaitTime > 0 {
-		time.Sleep(time.Duration(config.Configuration.Network.StakingWaitTime) * time.Second)
-	}
+	staking.WaitForStakingTransaction(testCase, tx)
 
 	// The ending balance of the account that created the validator should be less than the funded amount since the create validator tx should've used the specified amount for self delegation
 	accountEndingBalance, err := balances.GetShardBalance(validatorAccount.Address, testCase.StakingParameters.FromShardID)
@@ -110,9 +108,9 @@ func InvalidAddressScenario(testCase *testing.TestCase) {
 	testing.Title(testCase, "footer", testCase.Verbose)
 
 	if !testCase.StakingParameters.ReuseExistingValidator {
-		testing.Teardown(&validatorAccount, testCase.StakingParameters.FromShardID, config.Configuration.Funding.Account.Address, testCase.StakingParameters.FromShardID)
+		testing.Teardown(&validatorAccount, config.Configuration.Funding.Account.Address)
 	}
-	testing.Teardown(&delegatorAccount, testCase.StakingParameters.FromShardID, config.Configuration.Funding.Account.Address, testCase.StakingParameters.FromShardID)
+	testing.Teardown(&delegatorAccount, config.Configuration.Funding.Account.Address)
 
 	testCase.FinishedAt = time.Now().UTC()
 }
@@ -0,0 +1,89 @@
+package undelegate_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/mocknode"
+	"github.com/harmony-one/harmony-tf/mocknode/mocknodetest"
+	"github.com/harmony-one/harmony-tf/scenarios/staking/delegation/undelegate"
+	"github.com/harmony-one/harmony-tf/staking"
+	tfTesting "github.com/harmony-one/harmony-tf/testing"
+	"github.com/harmony-one/harmony/numeric"
+	"gopkg.in/yaml.v2"
+)
+
+const undelegateTestCase = `
+name: Undelegate - mock node
+execute: true
+expected: true
+scenario: staking/delegation/undelegate/standard
+staking_parameters:
+  create:
+    validator:
+      details:
+        name: Mock validator
+        identity: mock
+        website: https://harmony.one
+        security_contact: mock
+        details: Created by the mock node tests
+      commission:
+        rate: 0.1
+        max_rate: 0.9
+        max_change_rate: 0.05
+      minimum_self_delegation: 10000
+      maximum_total_delegation: 100000
+      amount: 10000
+    bls_key_count: 1
+  delegation:
+    delegate:
+      amount: 1000
+    undelegate:
+      amount: 1000
+`
+
+// TestStandardScenario - runs the standard undelegation scenario (create validator, delegate and undelegate) against the mock chain
+func TestStandardScenario(t *testing.T) {
+	chain := mocknodetest.Start(t, mocknode.Options{ChainID: big.NewInt(2), Shards: 2, BlockTime: 250 * time.Millisecond})
+	mocknodetest.UseFaucet(chain, numeric.NewDec(20000))
+
+	if err := funding.SetupFundingAccount(nil); err != nil {
+		t.Fatalf("failed to set up the funding account: %s", err.Error())
+	}
+
+	testCase := &tfTesting.TestCase{}
+	if err := yaml.Unmarshal([]byte(undelegateTestCase), testCase); err != nil {
+		t.Fatal(err)
+	}
+	testCase.Initialize()
+
+	undelegate.StandardScenario(testCase)
+
+	if testCase.Error != nil {
+		t.Fatalf("the scenario failed: %s", testCase.Error.Error())
+	}
+	if len(testCase.Transactions) != 3 {
+		t.Fatalf("expected a create validator, a delegation and an undelegation transaction, got %d transaction(s)", len(testCase.Transactions))
+	}
+	for _, tx := range testCase.Transactions {
+		if !tx.Success {
+			t.Errorf("expected staking transaction %s to succeed", tx.TransactionHash)
+		}
+	}
+	if !testCase.Result {
+		t.Fatal("expected the undelegation to succeed")
+	}
+
+	validator := testCase.StakingParameters.Create.Validator.Account
+	info, err := staking.ValidatorInformation(0, validator.Address)
+	if err != nil {
+		t.Fatalf("failed to retrieve the validator information: %s", err.Error())
+	}
+
+	// The delegation has been undelegated - only the self delegation remains
+	if total := staking.TotalDelegation(info); !total.Equal(numeric.NewDec(10000)) {
+		t.Errorf("expected a total delegation of 10000 after undelegating, got %f", total)
+	}
+}
@@ -0,0 +1,73 @@
+package create
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/staking"
+	"github.com/harmony-one/harmony-tf/testing"
+	"github.com/harmony-one/harmony-tf/testing/parameters"
+)
+
+// DescriptionBoundariesScenario - executes a create validator test case for every description field at, around and past its maximum length
+func DescriptionBoundariesScenario(testCase *testing.TestCase) {
+	testing.Title(testCase, "header", testCase.Verbose)
+	testCase.Executed = true
+	testCase.StartedAt = time.Now().UTC()
+
+	if testCase.ErrorOccurred(nil) {
+		return
+	}
+
+	base := testCase.StakingParameters.Create.Validator.Details
+	boundaryCases := parameters.GenerateDescriptionBoundaryCases(&base)
+
+	fundingMultiple := int64(1)
+	_, _, err := funding.CalculateFundingDetails(testCase.StakingParameters.Create.Validator.Amount.MulInt64(int64(len(boundaryCases))), fundingMultiple, 0)
+	if testCase.ErrorOccurred(err) {
+		return
+	}
+
+	successfulCases := 0
+	for i := range boundaryCases {
+		boundaryCase := &boundaryCases[i]
+		logger.StakingLog(fmt.Sprintf("Testing the %s field using the variant %s (%d bytes, max length: %d)", boundaryCase.Field, boundaryCase.Variant, len(boundaryCase.Value()), boundaryCase.MaxLength), testCase.Verbose)
+
+		validatorName := accounts.GenerateTestCaseAccountName(testCase.Name, fmt.Sprintf("Validator_%d", i))
+		account, err := testing.GenerateAndFundAccount(testCase, validatorName, testCase.StakingParameters.Create.Validator.Amount, fundingMultiple)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to generate and fund account %s", validatorName)
+			testCase.HandleError(err, &account, msg)
+			return
+		}
+
+		testCase.StakingParameters.Create.Validator.Account = &account
+		testCase.StakingParameters.Create.Validator.Details = boundaryCase.Details
+		tx, _, validatorExists, err := staking.BasicCreateValidator(testCase, &account, nil, nil)
+		if err == nil {
+			testCase.Transactions = append(testCase.Transactions, tx)
+		}
+		boundaryCase.Error = err
+		boundaryCase.Result = err == nil && tx.Success && validatorExists
+
+		if boundaryCase.Successful() {
+			successfulCases++
+		}
+
+		testing.Teardown(&account, config.Configuration.Funding.Account.Address)
+	}
+	testCase.StakingParameters.Create.Validator.Details = base
+
+	logger.StakingLog(fmt.Sprintf("A total of %d/%d description boundary cases behaved as expected:\n\n%s", successfulCases, len(boundaryCases), parameters.DescriptionBoundaryTable(boundaryCases)), testCase.Verbose)
+
+	testCase.Result = successfulCases == len(boundaryCases)
+
+	logger.ResultLog(testCase.Result, testCase.Expected, testCase.Verbose)
+	testing.Title(testCase, "footer", testCase.Verbose)
+
+	testCase.FinishedAt = time.Now().UTC()
+}
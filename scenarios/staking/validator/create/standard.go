@@ -46,9 +46,7 @@ func StandardScenario(testCase *testing.TestCase) {
 	}
 	testCase.Transactions = append(testCase.Transactions, tx)
 
-	if config.Configuration.Network.StakingWaitTime > 0 {
-		time.Sleep(time.Duration(config.Configuration.Network.StakingWaitTime) * time.Second)
-	}
+	staking.WaitForStakingTransaction(testCase, tx)
 
 	// The ending balance of the account that created the validator should be less than the funded amount since the create validator tx should've used the specified amount for self delegation
 	accountEndingBalance, _ := balances.GetShardBalance(account.Address, testCase.StakingParameters.FromShardID)
@@ -66,7 +64,7 @@ func StandardScenario(testCase *testing.TestCase) {
 	logger.ResultLog(testCase.Result, testCase.Expected, testCase.Verbose)
 	testing.Title(testCase, "footer", testCase.Verbose)
 
-	testing.Teardown(&account, testCase.StakingParameters.FromShardID, config.Configuration.Funding.Account.Address, testCase.StakingParameters.FromShardID)
+	testing.Teardown(&account, config.Configuration.Funding.Account.Address)
 
 	testCase.FinishedAt = time.Now().UTC()
 }
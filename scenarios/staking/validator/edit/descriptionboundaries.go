@@ -0,0 +1,84 @@
+package edit
+
+import (
+	"fmt"
+	"time"
+
+	sdkValidator "github.com/harmony-one/go-lib/staking/validator"
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/staking"
+	"github.com/harmony-one/harmony-tf/testing"
+	"github.com/harmony-one/harmony-tf/testing/parameters"
+)
+
+// DescriptionBoundariesScenario - executes an edit validator test case for every description field at, around and past its maximum length
+func DescriptionBoundariesScenario(testCase *testing.TestCase) {
+	testing.Title(testCase, "header", testCase.Verbose)
+	testCase.Executed = true
+	testCase.StartedAt = time.Now().UTC()
+
+	if testCase.ErrorOccurred(nil) {
+		return
+	}
+
+	_, _, err := funding.CalculateFundingDetails(testCase.StakingParameters.Create.Validator.Amount, 1, 0)
+	if testCase.ErrorOccurred(err) {
+		return
+	}
+
+	validatorName := accounts.GenerateTestCaseAccountName(testCase.Name, "Validator")
+	account, validator, err := staking.ReuseOrCreateValidator(testCase, validatorName)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to create validator using account %s", validatorName)
+		testCase.HandleError(err, account, msg)
+		return
+	}
+
+	if validator.Exists {
+		// Only the field under test is set - empty fields are left unchanged by edit validator transactions
+		boundaryCases := parameters.GenerateDescriptionBoundaryCases(nil)
+		node := config.Configuration.Network.API.NodeAddress(testCase.StakingParameters.FromShardID)
+
+		successfulCases := 0
+		for i := range boundaryCases {
+			boundaryCase := &boundaryCases[i]
+			logger.StakingLog(fmt.Sprintf("Testing the %s field using the variant %s (%d bytes, max length: %d)", boundaryCase.Field, boundaryCase.Variant, len(boundaryCase.Value()), boundaryCase.MaxLength), testCase.Verbose)
+
+			testCase.StakingParameters.Edit.Validator.Details = boundaryCase.Details
+			testCase.StakingParameters.Edit.Changes = parameters.EditValidatorChanges{}
+
+			editTx, err := staking.BasicEditValidator(testCase, validator.Account, nil, nil, nil)
+			if err == nil {
+				testCase.Transactions = append(testCase.Transactions, editTx)
+
+				var validatorResult sdkValidator.RPCValidatorResult
+				validatorResult, err = sdkValidator.Information(node, validator.Account.Address)
+				if err == nil {
+					boundaryCase.Result = editTx.Success && testCase.StakingParameters.Edit.EvaluateChanges(validatorResult.Validator, testCase.Verbose)
+				}
+			}
+			boundaryCase.Error = err
+
+			if boundaryCase.Successful() {
+				successfulCases++
+			}
+		}
+
+		logger.StakingLog(fmt.Sprintf("A total of %d/%d description boundary cases behaved as expected:\n\n%s", successfulCases, len(boundaryCases), parameters.DescriptionBoundaryTable(boundaryCases)), testCase.Verbose)
+
+		testCase.Result = successfulCases == len(boundaryCases)
+	}
+
+	if !testCase.StakingParameters.ReuseExistingValidator {
+		logger.TeardownLog("Performing test teardown (returning funds and removing accounts)", testCase.Verbose)
+		testing.Teardown(validator.Account, config.Configuration.Funding.Account.Address)
+	}
+
+	logger.ResultLog(testCase.Result, testCase.Expected, testCase.Verbose)
+	testing.Title(testCase, "footer", testCase.Verbose)
+
+	testCase.FinishedAt = time.Now().UTC()
+}
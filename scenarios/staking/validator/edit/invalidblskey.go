@@ -0,0 +1,87 @@
+package edit
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	sdkValidator "github.com/harmony-one/go-lib/staking/validator"
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/staking"
+	"github.com/harmony-one/harmony-tf/testing"
+)
+
+// InvalidBLSKeyScenario - executes an edit validator test case adding an invalid bls key (as defined by the edit mode) and checks if the validator's bls keys were changed
+func InvalidBLSKeyScenario(testCase *testing.TestCase) {
+	testing.Title(testCase, "header", testCase.Verbose)
+	testCase.Executed = true
+	testCase.StartedAt = time.Now().UTC()
+
+	if testCase.ErrorOccurred(nil) {
+		return
+	}
+
+	_, _, err := funding.CalculateFundingDetails(testCase.StakingParameters.Create.Validator.Amount, 1, 0)
+	if testCase.ErrorOccurred(err) {
+		return
+	}
+
+	validatorName := accounts.GenerateTestCaseAccountName(testCase.Name, "Validator")
+	account, validator, err := staking.ReuseOrCreateValidator(testCase, validatorName)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to create validator using account %s", validatorName)
+		testCase.HandleError(err, account, msg)
+		return
+	}
+
+	if validator.Exists {
+		node := config.Configuration.Network.API.NodeAddress(testCase.StakingParameters.FromShardID)
+		startingValidatorResult, err := sdkValidator.Information(node, validator.Account.Address)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to retrieve validator info for validator %s", validator.Account.Address)
+			testCase.HandleError(err, validator.Account, msg)
+			return
+		}
+
+		blsKeyToRemove, blsKeyToAdd, err := staking.ManageBLSKeys(validator, testCase.StakingParameters.Edit.Mode, testCase.StakingParameters.Create.BLSSignatureMessage, testCase.Verbose)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to generate invalid bls key to use for adding to existing validator %s", validator.Account.Address)
+			testCase.HandleError(err, validator.Account, msg)
+			return
+		}
+
+		editTx, err := staking.BasicEditValidator(testCase, validator.Account, nil, blsKeyToRemove, blsKeyToAdd)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to edit validator using account %s, address: %s", validator.Account.Name, validator.Account.Address)
+			testCase.HandleError(err, validator.Account, msg)
+			return
+		}
+		testCase.Transactions = append(testCase.Transactions, editTx)
+
+		endingValidatorResult, err := sdkValidator.Information(node, validator.Account.Address)
+		if err != nil {
+			msg := fmt.Sprintf("Failed to retrieve validator info for validator %s", validator.Account.Address)
+			testCase.HandleError(err, validator.Account, msg)
+			return
+		}
+
+		blsKeysChanged := !reflect.DeepEqual(startingValidatorResult.Validator.BLSPublicKeys, endingValidatorResult.Validator.BLSPublicKeys)
+		blsKeysChangedColoring := logger.ResultColoring(blsKeysChanged, testCase.Expected)
+		logger.StakingLog(fmt.Sprintf("Validator %s had %d bls keys before and %d bls keys after the edit - bls keys changed: %s", validator.Account.Address, len(startingValidatorResult.Validator.BLSPublicKeys), len(endingValidatorResult.Validator.BLSPublicKeys), blsKeysChangedColoring), testCase.Verbose)
+
+		testCase.Result = editTx.Success && blsKeysChanged
+	}
+
+	if !testCase.StakingParameters.ReuseExistingValidator {
+		logger.TeardownLog("Performing test teardown (returning funds and removing accounts)", testCase.Verbose)
+		testing.Teardown(validator.Account, config.Configuration.Funding.Account.Address)
+	}
+
+	logger.ResultLog(testCase.Result, testCase.Expected, testCase.Verbose)
+	testing.Title(testCase, "footer", testCase.Verbose)
+
+	testCase.FinishedAt = time.Now().UTC()
+}
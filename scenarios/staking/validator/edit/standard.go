@@ -81,7 +81,7 @@ func StandardScenario(testCase *testing.TestCase) {
 
 	if !testCase.StakingParameters.ReuseExistingValidator {
 		logger.TeardownLog("Performing test teardown (returning funds and removing accounts)", testCase.Verbose)
-		testing.Teardown(validator.Account, testCase.StakingParameters.FromShardID, config.Configuration.Funding.Account.Address, testCase.StakingParameters.FromShardID)
+		testing.Teardown(validator.Account, config.Configuration.Funding.Account.Address)
 	}
 
 	logger.ResultLog(testCase.Result, testCase.Expected, testCase.Verbose)
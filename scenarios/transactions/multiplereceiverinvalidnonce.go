@@ -18,8 +18,6 @@ import (
 	sdkTxs "github.com/harmony-one/go-lib/transactions"
 )
 
-// Due to rate-limiting by RPC/explorer endpoints - don't run concurrently for now
-
 // MultipleReceiverInvalidNonceScenario - runs a tests where multiple receiver wallets receive txs with the exact same nonce
 func MultipleReceiverInvalidNonceScenario(testCase *testing.TestCase) {
 	testing.Title(testCase, "header", testCase.Verbose)
@@ -152,9 +150,9 @@ func multipleReceiversTeardown(testCase *testing.TestCase, senderAccount sdkAcco
 	var waitGroup sync.WaitGroup
 	waitGroup.Add(1 + len(receiverAccounts))
 
-	go testing.AsyncTeardown(&senderAccount, testCase.Parameters.FromShardID, config.Configuration.Funding.Account.Address, testCase.Parameters.ToShardID, &waitGroup)
+	go testing.AsyncTeardown(&senderAccount, config.Configuration.Funding.Account.Address, &waitGroup)
 	for _, receiverAccount := range receiverAccounts {
-		go testing.AsyncTeardown(&receiverAccount, testCase.Parameters.ToShardID, config.Configuration.Funding.Account.Address, testCase.Parameters.FromShardID, &waitGroup)
+		go testing.AsyncTeardown(&receiverAccount, config.Configuration.Funding.Account.Address, &waitGroup)
 	}
 
 	waitGroup.Wait()
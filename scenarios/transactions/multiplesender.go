@@ -18,8 +18,6 @@ import (
 	sdkTxs "github.com/harmony-one/go-lib/transactions"
 )
 
-// Due to rate-limiting by RPC/explorer endpoints - don't run concurrently for now
-
 // MultipleSenderScenario - runs a tests where multiple sender wallets are used to send to one respective new wallet
 func MultipleSenderScenario(testCase *testing.TestCase) {
 	testing.Title(testCase, "header", testCase.Verbose)
@@ -167,9 +165,9 @@ func multipleSendersTeardown(testCase *testing.TestCase, senderAccounts []sdkAcc
 	waitGroup.Add(1 + len(senderAccounts))
 
 	for _, senderAccount := range senderAccounts {
-		go testing.AsyncTeardown(&senderAccount, testCase.Parameters.FromShardID, config.Configuration.Funding.Account.Address, testCase.Parameters.FromShardID, &waitGroup)
+		go testing.AsyncTeardown(&senderAccount, config.Configuration.Funding.Account.Address, &waitGroup)
 	}
-	go testing.AsyncTeardown(&receiverAccount, testCase.Parameters.ToShardID, config.Configuration.Funding.Account.Address, testCase.Parameters.FromShardID, &waitGroup)
+	go testing.AsyncTeardown(&receiverAccount, config.Configuration.Funding.Account.Address, &waitGroup)
 
 	waitGroup.Wait()
 }
@@ -82,10 +82,7 @@ func SameAccountScenario(testCase *testing.TestCase) {
 
 	logger.TransactionLog(fmt.Sprintf("Sent %f token(s) from %s (shard %d) to %s (shard %d) - transaction hash: %s, tx successful: %s", testCase.Parameters.Amount, account.Address, testCase.Parameters.FromShardID, account.Address, testCase.Parameters.ToShardID, testCaseTx.TransactionHash, txResultColoring), testCase.Verbose)
 
-	/*if testCaseTx.Success && testCase.Parameters.FromShardID != testCase.Parameters.ToShardID {
-		logger.BalanceLog(fmt.Sprintf("Because this is a cross shard transaction we need to wait an extra %d seconds to correctly receive the ending balance of the receiver account %s in shard %d", config.Configuration.Network.CrossShardTxWaitTime, account.Address, testCase.Parameters.ToShardID), testCase.Verbose)
-		time.Sleep(time.Duration(config.Configuration.Network.CrossShardTxWaitTime) * time.Second)
-	}*/
+	transactions.WaitForCrossShardTransaction(testCaseTx, testCase.Verbose)
 
 	receiverEndingBalance, err := balances.GetNonZeroShardBalance(account.Address, testCase.Parameters.ToShardID)
 	if testCase.ErrorOccurred(err) {
@@ -107,7 +104,7 @@ func SameAccountScenario(testCase *testing.TestCase) {
 	logger.ResultLog(testCase.Result, testCase.Expected, testCase.Verbose)
 	testing.Title(testCase, "footer", testCase.Verbose)
 
-	testing.Teardown(&account, testCase.Parameters.ToShardID, config.Configuration.Funding.Account.Address, testCase.Parameters.FromShardID)
+	testing.Teardown(&account, config.Configuration.Funding.Account.Address)
 
 	testCase.FinishedAt = time.Now().UTC()
 }
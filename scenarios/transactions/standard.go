@@ -79,10 +79,7 @@ func StandardScenario(testCase *testing.TestCase) {
 		return
 	}
 
-	/*if testCaseTx.Success && testCase.Parameters.FromShardID != testCase.Parameters.ToShardID {
-		logger.TransactionLog(fmt.Sprintf("Because this is a cross shard transaction we need to wait an extra %d seconds to correctly receive the ending balance of the receiver account %s in shard %d", config.Configuration.Network.CrossShardTxWaitTime, receiverAccount.Address, testCase.Parameters.ToShardID), testCase.Verbose)
-		time.Sleep(time.Duration(config.Configuration.Network.CrossShardTxWaitTime) * time.Second)
-	}*/
+	transactions.WaitForCrossShardTransaction(testCaseTx, testCase.Verbose)
 
 	receiverEndingBalance, err := balances.GetNonZeroShardBalance(receiverAccount.Address, testCase.Parameters.ToShardID)
 	if testCase.ErrorOccurred(err) {
@@ -107,8 +104,8 @@ func standardTeardown(testCase *testing.TestCase, senderAccount sdkAccounts.Acco
 	var waitGroup sync.WaitGroup
 	waitGroup.Add(2)
 
-	go testing.AsyncTeardown(&senderAccount, testCase.Parameters.FromShardID, config.Configuration.Funding.Account.Address, testCase.Parameters.FromShardID, &waitGroup)
-	go testing.AsyncTeardown(&receiverAccount, testCase.Parameters.ToShardID, config.Configuration.Funding.Account.Address, testCase.Parameters.FromShardID, &waitGroup)
+	go testing.AsyncTeardown(&senderAccount, config.Configuration.Funding.Account.Address, &waitGroup)
+	go testing.AsyncTeardown(&receiverAccount, config.Configuration.Funding.Account.Address, &waitGroup)
 
 	waitGroup.Wait()
 }
@@ -0,0 +1,60 @@
+package transactions_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/mocknode"
+	"github.com/harmony-one/harmony-tf/mocknode/mocknodetest"
+	"github.com/harmony-one/harmony-tf/scenarios/transactions"
+	tfTesting "github.com/harmony-one/harmony-tf/testing"
+	"github.com/harmony-one/harmony/numeric"
+	"gopkg.in/yaml.v2"
+)
+
+const crossShardTestCase = `
+name: Cross shard transfer - mock node
+execute: true
+expected: true
+scenario: transactions/standard
+parameters:
+  receiver_count: 1
+  from_shard_id: 0
+  to_shard_id: 1
+  amount: 1.5
+  gas:
+    limit: 21000
+    price: 1
+  nonce: -1
+  timeout: 10
+`
+
+// TestStandardScenario - runs a cross shard transfer using the standard scenario against the mock chain
+func TestStandardScenario(t *testing.T) {
+	chain := mocknodetest.Start(t, mocknode.Options{ChainID: big.NewInt(2), Shards: 2, BlockTime: 250 * time.Millisecond})
+	mocknodetest.UseFaucet(chain, numeric.NewDec(100))
+
+	if err := funding.SetupFundingAccount(nil); err != nil {
+		t.Fatalf("failed to set up the funding account: %s", err.Error())
+	}
+
+	testCase := &tfTesting.TestCase{}
+	if err := yaml.Unmarshal([]byte(crossShardTestCase), testCase); err != nil {
+		t.Fatal(err)
+	}
+	testCase.Initialize()
+
+	transactions.StandardScenario(testCase)
+
+	if testCase.Error != nil {
+		t.Fatalf("the scenario failed: %s", testCase.Error.Error())
+	}
+	if len(testCase.Transactions) != 1 || !testCase.Transactions[0].Success {
+		t.Fatalf("expected a single successful transaction, got %+v", testCase.Transactions)
+	}
+	if !testCase.Result {
+		t.Error("expected the receiver to end up with the transferred amount in shard 1")
+	}
+}
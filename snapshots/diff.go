@@ -0,0 +1,320 @@
+package snapshots
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+// Report - the before/after snapshots of all accounts touched by a test case
+type Report struct {
+	TestCase string
+	Accounts []AccountReport
+}
+
+// AccountReport - the before/after snapshots of a single account and the changes between them
+type AccountReport struct {
+	Address string
+	Name    string
+	Before  *State
+	After   *State
+	Changes []Change
+}
+
+// Change - a value that changed during a test case - Unexpected changes can't be explained by what the framework sent
+type Change struct {
+	Field      string
+	Before     string
+	After      string
+	Unexpected bool
+	Reason     string
+}
+
+// Changes - returns the total number of changes and the number of unexpected changes
+func (report *Report) Changes() (changes int, unexpected int) {
+	if report == nil {
+		return 0, 0
+	}
+
+	for _, account := range report.Accounts {
+		for _, change := range account.Changes {
+			changes++
+			if change.Unexpected {
+				unexpected++
+			}
+		}
+	}
+
+	return changes, unexpected
+}
+
+// Unexpected - returns a summary of every unexpected change
+func (report *Report) Unexpected() (summaries []string) {
+	if report == nil {
+		return nil
+	}
+
+	for _, account := range report.Accounts {
+		for _, change := range account.Changes {
+			if change.Unexpected {
+				summaries = append(summaries, fmt.Sprintf("%s %s: %s -> %s (%s)", account.Label(), change.Field, change.Before, change.After, change.Reason))
+			}
+		}
+	}
+
+	return summaries
+}
+
+// String - formats the diff of all touched accounts
+func (report *Report) String() string {
+	var builder strings.Builder
+	format := "%-3s %-50s %-30s %-25s %-25s %s\n"
+	builder.WriteString(fmt.Sprintf(format, "", "Account", "Value", "Before", "After", "Reason"))
+	builder.WriteString(fmt.Sprintf("%s\n", strings.Repeat("-", 50)))
+
+	for _, account := range report.Accounts {
+		if len(account.Changes) == 0 {
+			builder.WriteString(fmt.Sprintf(format, "", account.Label(), "(unchanged)", "", "", ""))
+		}
+
+		for _, change := range account.Changes {
+			marker := ""
+			if change.Unexpected {
+				marker = "!"
+			}
+			builder.WriteString(fmt.Sprintf(format, marker, account.Label(), change.Field, change.Before, change.After, change.Reason))
+		}
+
+		for _, state := range []*State{account.Before, account.After} {
+			for _, err := range state.Errors {
+				builder.WriteString(fmt.Sprintf(format, "?", account.Label(), "(not captured)", "", "", err))
+			}
+		}
+	}
+
+	return builder.String()
+}
+
+// Label - the account name (if known) and address
+func (account *AccountReport) Label() string {
+	if account.Name != "" {
+		return fmt.Sprintf("%s (%s)", account.Name, account.Address)
+	}
+
+	return account.Address
+}
+
+// diff - compares the state of an account before and after the test case against what the framework sent from and to it
+func (account *account) diff(before *State, after *State, expectEmpty bool) (changes []Change) {
+	tolerance := config.Configuration.Framework.Snapshots.Tolerance
+	released := numeric.NewDec(0)
+	if before.delegationsKnown && after.delegationsKnown {
+		released = releasedUndelegations(before.Delegations, after.Delegations)
+	}
+
+	for _, shardID := range shardIDs(before, after) {
+		shard := account.shards[shardID]
+		if shard == nil {
+			shard = &activity{spent: numeric.NewDec(0), received: numeric.NewDec(0)}
+		}
+
+		received := shard.received
+		if shardID == 0 {
+			received = received.Add(released)
+		}
+
+		if previous, ok := before.Balances[shardID]; ok {
+			if balance, ok := after.Balances[shardID]; ok {
+				change := Change{Field: fmt.Sprintf("balance (shard %d)", shardID), Before: fmt.Sprintf("%f", previous), After: fmt.Sprintf("%f", balance)}
+				delta := balance.Sub(previous)
+
+				switch {
+				case delta.IsPositive() && !shard.staked && delta.GT(received.Add(tolerance)):
+					change.Unexpected = true
+					change.Reason = fmt.Sprintf("increased by %f but only %f was sent to it or released from undelegations", delta, received)
+				case delta.IsNegative() && delta.Neg().GT(shard.spent.Add(tolerance)):
+					change.Unexpected = true
+					change.Reason = fmt.Sprintf("decreased by %f but only %f was sent, staked or spent on gas", delta.Neg(), shard.spent)
+				case expectEmpty && balance.GT(config.Configuration.Funding.Gas.Cost):
+					change.Unexpected = true
+					change.Reason = "funds were left behind after the teardown"
+				}
+
+				if !delta.IsZero() || change.Unexpected {
+					changes = append(changes, change)
+				}
+			}
+		}
+
+		if previous, ok := before.Nonces[shardID]; ok {
+			if nonce, ok := after.Nonces[shardID]; ok && nonce != previous {
+				change := Change{Field: fmt.Sprintf("nonce (shard %d)", shardID), Before: fmt.Sprintf("%d", previous), After: fmt.Sprintf("%d", nonce)}
+
+				switch {
+				case nonce < previous:
+					change.Unexpected = true
+					change.Reason = "decreased"
+				case nonce-previous > uint64(shard.sent):
+					change.Unexpected = true
+					change.Reason = fmt.Sprintf("increased by %d but only %d transaction(s) were sent", nonce-previous, shard.sent)
+				}
+
+				changes = append(changes, change)
+			}
+		}
+	}
+
+	if before.validatorKnown && after.validatorKnown {
+		changes = append(changes, account.diffValidator(before.Validator, after.Validator)...)
+	}
+
+	if before.delegationsKnown && after.delegationsKnown {
+		changes = append(changes, account.diffDelegations(before.Delegations, after.Delegations)...)
+	}
+
+	return changes
+}
+
+func (account *account) diffValidator(before *Validator, after *Validator) (changes []Change) {
+	if before == nil && after == nil {
+		return nil
+	}
+
+	if before == nil || after == nil {
+		change := Change{Field: "validator", Before: "none", After: "none"}
+		if before != nil {
+			change.Before = before.Name
+		}
+		if after != nil {
+			change.After = after.Name
+		}
+		if !account.validator {
+			change.Unexpected = true
+			change.Reason = "no create validator transaction was sent"
+		}
+		return []Change{change}
+	}
+
+	fields := []struct {
+		name     string
+		before   string
+		after    string
+		expected bool
+	}{
+		{"validator name", before.Name, after.Name, account.validator},
+		{"validator status", before.Status, after.Status, account.validator},
+		{"validator bls keys", fmt.Sprintf("%d", before.BLSKeys), fmt.Sprintf("%d", after.BLSKeys), account.validator},
+		{"validator rate", formatDec(before.Rate), formatDec(after.Rate), account.validator},
+		{"min self delegation", formatDec(before.MinSelfDelegation), formatDec(after.MinSelfDelegation), account.validator},
+		{"max total delegation", formatDec(before.MaxTotalDelegation), formatDec(after.MaxTotalDelegation), account.validator},
+		{"total delegation", formatDec(before.TotalDelegation), formatDec(after.TotalDelegation), account.validator || account.delegated},
+	}
+
+	for _, field := range fields {
+		if field.before == field.after {
+			continue
+		}
+
+		change := Change{Field: field.name, Before: field.before, After: field.after}
+		if !field.expected {
+			change.Unexpected = true
+			change.Reason = "no staking transaction was sent for this validator"
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+func (account *account) diffDelegations(before map[string]Delegation, after map[string]Delegation) (changes []Change) {
+	validators := []string{}
+	for validatorAddress := range before {
+		validators = append(validators, validatorAddress)
+	}
+	for validatorAddress := range after {
+		if _, ok := before[validatorAddress]; !ok {
+			validators = append(validators, validatorAddress)
+		}
+	}
+	sort.Strings(validators)
+
+	zero := Delegation{Amount: numeric.NewDec(0), Undelegating: numeric.NewDec(0)}
+	for _, validatorAddress := range validators {
+		previous, ok := before[validatorAddress]
+		if !ok {
+			previous = zero
+		}
+		current, ok := after[validatorAddress]
+		if !ok {
+			current = zero
+		}
+
+		expected := account.delegateTo[validatorAddress]
+
+		if !previous.Amount.Equal(current.Amount) {
+			change := Change{Field: fmt.Sprintf("delegation to %s", validatorAddress), Before: formatDec(previous.Amount), After: formatDec(current.Amount)}
+			if !expected {
+				change.Unexpected = true
+				change.Reason = "no delegate or undelegate transaction was sent for this validator"
+			}
+			changes = append(changes, change)
+		}
+
+		if !previous.Undelegating.Equal(current.Undelegating) {
+			change := Change{Field: fmt.Sprintf("undelegating from %s", validatorAddress), Before: formatDec(previous.Undelegating), After: formatDec(current.Undelegating)}
+			// Pending undelegations are released by the network at the end of their lock period
+			if !expected && current.Undelegating.GT(previous.Undelegating) {
+				change.Unexpected = true
+				change.Reason = "no undelegate transaction was sent for this validator"
+			}
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// releasedUndelegations - the total amount of pending undelegations that were released (paid out) during the test case
+func releasedUndelegations(before map[string]Delegation, after map[string]Delegation) numeric.Dec {
+	released := numeric.NewDec(0)
+	for validatorAddress, previous := range before {
+		current, ok := after[validatorAddress]
+		if !ok {
+			released = released.Add(previous.Undelegating)
+		} else if previous.Undelegating.GT(current.Undelegating) {
+			released = released.Add(previous.Undelegating.Sub(current.Undelegating))
+		}
+	}
+
+	return released
+}
+
+func shardIDs(states ...*State) (shardIDs []uint32) {
+	seen := make(map[uint32]bool)
+	for _, state := range states {
+		for shardID := range state.Balances {
+			seen[shardID] = true
+		}
+		for shardID := range state.Nonces {
+			seen[shardID] = true
+		}
+	}
+
+	for shardID := range seen {
+		shardIDs = append(shardIDs, shardID)
+	}
+	sort.Slice(shardIDs, func(i, j int) bool { return shardIDs[i] < shardIDs[j] })
+
+	return shardIDs
+}
+
+func formatDec(value numeric.Dec) string {
+	if value.IsNil() {
+		return ""
+	}
+
+	return fmt.Sprintf("%f", value)
+}
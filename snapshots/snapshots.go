@@ -0,0 +1,210 @@
+package snapshots
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+var current = &tracker{}
+
+// tracker - keeps track of the accounts touched by the current test case and what the framework did with them
+type tracker struct {
+	mutex    sync.Mutex
+	testCase string
+	active   bool
+	accounts map[string]*account
+}
+
+// account - an account touched by the current test case - its state is captured the first time it's touched, i.e. before the test case changed it
+type account struct {
+	address    string
+	name       string
+	capture    sync.Once
+	before     *State
+	shards     map[uint32]*activity
+	validator  bool
+	delegated  bool
+	delegateTo map[string]bool
+}
+
+// activity - what the framework sent from and to an account in a given shard
+type activity struct {
+	sent     int
+	spent    numeric.Dec
+	received numeric.Dec
+	staked   bool
+}
+
+// Enabled - whether or not snapshots are taken
+func Enabled() bool {
+	return config.Configuration.Framework.Snapshots.Enabled
+}
+
+// Start - starts tracking the accounts touched by a given test case - the funding account is always included
+func Start(testCase string) {
+	if !Enabled() {
+		return
+	}
+
+	current.mutex.Lock()
+	current.testCase = testCase
+	current.active = true
+	current.accounts = make(map[string]*account)
+	current.mutex.Unlock()
+
+	if funding := config.Configuration.Funding.Account; funding.Address != "" {
+		current.touch(funding.Address, funding.Name)
+	}
+}
+
+// Finish - captures the state of all touched accounts after the test case and compares it to the state before the test case
+// Accounts listed in torndown are expected to have returned their funds, i.e. to hold no more than the funding gas cost in any shard
+func Finish(torndown []string) *Report {
+	current.mutex.Lock()
+	if !current.active {
+		current.mutex.Unlock()
+		return nil
+	}
+	current.active = false
+	accounts := make([]*account, 0, len(current.accounts))
+	for _, account := range current.accounts {
+		accounts = append(accounts, account)
+	}
+	testCase := current.testCase
+	current.mutex.Unlock()
+
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].address < accounts[j].address })
+
+	expectEmpty := make(map[string]bool)
+	for _, address := range torndown {
+		expectEmpty[address] = true
+	}
+
+	report := &Report{TestCase: testCase}
+	for _, account := range accounts {
+		account.capture.Do(func() { account.before = Capture(account.address) })
+		report.Accounts = append(report.Accounts, AccountReport{
+			Address: account.address,
+			Name:    account.name,
+			Before:  account.before,
+			After:   Capture(account.address),
+		})
+	}
+
+	current.mutex.Lock()
+	for i, account := range accounts {
+		report.Accounts[i].Changes = account.diff(report.Accounts[i].Before, report.Accounts[i].After, expectEmpty[account.address])
+	}
+	current.mutex.Unlock()
+
+	return report
+}
+
+// Transfer - records a transaction that's about to be sent and captures the state of both accounts if they haven't been touched before
+func Transfer(fromAddress string, fromName string, fromShardID uint32, toAddress string, toShardID uint32, amount numeric.Dec, gasLimit int64, gasPrice numeric.Dec) {
+	if sender := current.touch(fromAddress, fromName); sender != nil {
+		current.mutex.Lock()
+		shard := sender.shard(fromShardID)
+		shard.sent++
+		shard.spent = shard.spent.Add(nonNil(amount)).Add(maximumGasCost(gasLimit, gasPrice))
+		current.mutex.Unlock()
+	}
+
+	if receiver := current.touch(toAddress, ""); receiver != nil {
+		current.mutex.Lock()
+		shard := receiver.shard(toShardID)
+		shard.received = shard.received.Add(nonNil(amount))
+		current.mutex.Unlock()
+	}
+}
+
+// Stake - records a staking transaction that's about to be sent
+// delegatorAddress is the validator itself for validator creations (self delegation) and empty for validator edits - amount is the amount that gets locked, i.e. zero for edits and undelegations
+func Stake(senderAddress string, senderName string, shardID uint32, validatorAddress string, delegatorAddress string, amount numeric.Dec, gasLimit int64, gasPrice numeric.Dec) {
+	if sender := current.touch(senderAddress, senderName); sender != nil {
+		current.mutex.Lock()
+		shard := sender.shard(shardID)
+		shard.sent++
+		shard.spent = shard.spent.Add(maximumGasCost(gasLimit, gasPrice))
+		current.mutex.Unlock()
+	}
+
+	validator := current.touch(validatorAddress, "")
+	if validator != nil {
+		current.mutex.Lock()
+		if delegatorAddress == "" || delegatorAddress == validatorAddress {
+			validator.validator = true
+		}
+		validator.delegated = validator.delegated || delegatorAddress != ""
+		current.mutex.Unlock()
+	}
+
+	if delegator := current.touch(delegatorAddress, ""); delegator != nil {
+		current.mutex.Lock()
+		delegator.delegateTo[validatorAddress] = true
+		// Delegated amounts are locked and undelegated amounts (and rewards) are paid out by the beacon chain
+		shard := delegator.shard(shardID)
+		shard.staked = true
+		shard.spent = shard.spent.Add(nonNil(amount))
+		current.mutex.Unlock()
+	}
+}
+
+// touch - registers an account as touched by the current test case and captures its state the first time it's touched
+// Concurrent callers touching the same account for the first time wait for the capture so that no transaction can be sent before it
+func (tracker *tracker) touch(address string, name string) *account {
+	if address == "" {
+		return nil
+	}
+
+	tracker.mutex.Lock()
+	if !tracker.active {
+		tracker.mutex.Unlock()
+		return nil
+	}
+
+	touched, ok := tracker.accounts[address]
+	if !ok {
+		touched = &account{address: address, shards: make(map[uint32]*activity), delegateTo: make(map[string]bool)}
+		tracker.accounts[address] = touched
+	}
+	if touched.name == "" {
+		touched.name = name
+	}
+	tracker.mutex.Unlock()
+
+	touched.capture.Do(func() { touched.before = Capture(address) })
+
+	return touched
+}
+
+// shard - returns the activity of an account in a given shard - the tracker lock has to be held
+func (account *account) shard(shardID uint32) *activity {
+	shard, ok := account.shards[shardID]
+	if !ok {
+		shard = &activity{spent: numeric.NewDec(0), received: numeric.NewDec(0)}
+		account.shards[shardID] = shard
+	}
+
+	return shard
+}
+
+// maximumGasCost - the maximum gas cost of a transaction - gas prices are specified in nano denominations
+func maximumGasCost(gasLimit int64, gasPrice numeric.Dec) numeric.Dec {
+	if gasPrice.IsNil() {
+		return numeric.NewDec(0)
+	}
+
+	return numeric.NewDec(gasLimit).Mul(gasPrice).Quo(numeric.NewDec(1000000000))
+}
+
+func nonNil(amount numeric.Dec) numeric.Dec {
+	if amount.IsNil() {
+		return numeric.NewDec(0)
+	}
+
+	return amount
+}
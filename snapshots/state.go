@@ -0,0 +1,126 @@
+package snapshots
+
+import (
+	"fmt"
+	"strings"
+
+	sdkDelegation "github.com/harmony-one/go-lib/staking/delegation"
+	sdkValidator "github.com/harmony-one/go-lib/staking/validator"
+	"github.com/harmony-one/harmony-tf/balances"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/network"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+// State - the state of an account at a given point in time
+type State struct {
+	Balances    map[uint32]numeric.Dec
+	Nonces      map[uint32]uint64
+	Validator   *Validator
+	Delegations map[string]Delegation
+	Errors      []string
+
+	validatorKnown   bool
+	delegationsKnown bool
+}
+
+// Validator - the validator state of an account - nil if the account isn't a validator
+type Validator struct {
+	Name               string
+	Status             string
+	BLSKeys            int
+	Rate               numeric.Dec
+	MinSelfDelegation  numeric.Dec
+	MaxTotalDelegation numeric.Dec
+	TotalDelegation    numeric.Dec
+}
+
+// Delegation - a delegation of an account to a given validator
+type Delegation struct {
+	Amount       numeric.Dec
+	Undelegating numeric.Dec
+}
+
+// Capture - captures the per-shard balances and nonces as well as the validator and delegation state of a given address
+// Failed lookups don't abort the capture - they're recorded in State.Errors and the affected values are left out of the diff
+func Capture(address string) *State {
+	state := &State{
+		Balances:    make(map[uint32]numeric.Dec),
+		Nonces:      make(map[uint32]uint64),
+		Delegations: make(map[string]Delegation),
+	}
+
+	for shard := 0; shard < config.Configuration.Network.Shards; shard++ {
+		shardID := uint32(shard)
+
+		balance, err := balances.GetShardBalance(address, shardID)
+		if err != nil {
+			state.addError("balance", shardID, err)
+		} else if !balance.IsNil() {
+			state.Balances[shardID] = balance
+		}
+
+		nonce, err := currentNonce(address, shardID)
+		if err != nil {
+			state.addError("nonce", shardID, err)
+		} else {
+			state.Nonces[shardID] = nonce
+		}
+	}
+
+	// Staking state only lives on the beacon chain
+	node := config.Configuration.Network.API.NodeAddress(0)
+
+	information, err := sdkValidator.Information(node, address)
+	switch {
+	case err == nil:
+		state.validatorKnown = true
+		state.Validator = &Validator{
+			Name:               information.Validator.Name,
+			Status:             information.Validator.EligibilityStatus,
+			BLSKeys:            len(information.Validator.BLSPublicKeys),
+			Rate:               information.Validator.Rate,
+			MinSelfDelegation:  information.Validator.MinSelfDelegation,
+			MaxTotalDelegation: information.Validator.MaxTotalDelegation,
+			TotalDelegation:    information.TotalDelegation,
+		}
+	case strings.Contains(err.Error(), "does not exist"):
+		state.validatorKnown = true
+	default:
+		state.addError("validator", 0, err)
+	}
+
+	delegations, err := sdkDelegation.ByDelegator(node, address)
+	if err != nil {
+		state.addError("delegations", 0, err)
+	} else {
+		state.delegationsKnown = true
+	}
+
+	for _, delegation := range delegations {
+		undelegating := numeric.NewDec(0)
+		for _, undelegation := range delegation.Undelegations {
+			undelegating = undelegating.Add(balances.FromAtto(undelegation.RawAmount))
+		}
+
+		state.Delegations[delegation.ValidatorAddress] = Delegation{
+			Amount:       balances.FromAtto(delegation.RawAmount),
+			Undelegating: undelegating,
+		}
+	}
+
+	return state
+}
+
+func (state *State) addError(value string, shardID uint32, err error) {
+	state.Errors = append(state.Errors, fmt.Sprintf("%s, shard %d: %s", value, shardID, err.Error()))
+}
+
+func currentNonce(address string, shardID uint32) (uint64, error) {
+	result, err := network.Call(config.Configuration.Network.API.NodeAddress(shardID), "hmy_getTransactionCount", address, "latest")
+	if err != nil {
+		return 0, err
+	}
+
+	return network.ParseQuantity(result)
+}
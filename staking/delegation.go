@@ -7,7 +7,10 @@ import (
 	sdkNetworkNonce "github.com/harmony-one/go-lib/network/rpc/nonces"
 	sdkDelegation "github.com/harmony-one/go-lib/staking/delegation"
 	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/confirmations"
+	"github.com/harmony-one/harmony-tf/snapshots"
 	testParams "github.com/harmony-one/harmony-tf/testing/parameters"
+	"github.com/harmony-one/harmony-tf/transactions"
 	"github.com/harmony-one/harmony/numeric"
 )
 
@@ -55,37 +58,46 @@ func executeDelegationMethod(method string, delegator *sdkAccounts.Account, vali
 		currentNonce = uint64(params.Nonce)
 	}
 
+	gasLimit := params.Delegation.Delegate.Gas.Limit
+	gasPrice := params.Delegation.Delegate.Gas.Price
+	if method == "undelegate" {
+		gasLimit = params.Delegation.Undelegate.Gas.Limit
+		gasPrice = params.Delegation.Undelegate.Gas.Price
+	}
+
 	if method == "delegate" {
+		snapshots.Stake(account.Address, account.Name, params.FromShardID, validator.Address, delegator.Address, params.Delegation.Delegate.Amount, gasLimit, gasPrice)
 		txResult, err = sdkDelegation.Delegate(
 			account.Keystore,
 			account.Account,
 			rpcClient,
-			config.Configuration.Network.API.ChainID,
+			config.Configuration.Network.StakingChainID,
 			delegator.Address,
 			validator.Address,
 			params.Delegation.Delegate.Amount,
-			params.Delegation.Delegate.Gas.Limit,
-			params.Delegation.Delegate.Gas.Price,
+			gasLimit,
+			gasPrice,
 			currentNonce,
 			config.Configuration.Account.Passphrase,
 			config.Configuration.Network.API.NodeAddress(params.FromShardID),
-			params.Timeout,
+			confirmations.SendTimeout(params.Timeout),
 		)
 	} else if method == "undelegate" {
+		snapshots.Stake(account.Address, account.Name, params.FromShardID, validator.Address, delegator.Address, numeric.NewDec(0), gasLimit, gasPrice)
 		txResult, err = sdkDelegation.Undelegate(
 			account.Keystore,
 			account.Account,
 			rpcClient,
-			config.Configuration.Network.API.ChainID,
+			config.Configuration.Network.StakingChainID,
 			delegator.Address,
 			validator.Address,
 			params.Delegation.Undelegate.Amount,
-			params.Delegation.Undelegate.Gas.Limit,
-			params.Delegation.Undelegate.Gas.Price,
+			gasLimit,
+			gasPrice,
 			currentNonce,
 			config.Configuration.Account.Passphrase,
 			config.Configuration.Network.API.NodeAddress(params.FromShardID),
-			params.Timeout,
+			confirmations.SendTimeout(params.Timeout),
 		)
 	}
 
@@ -93,6 +105,14 @@ func executeDelegationMethod(method string, delegator *sdkAccounts.Account, vali
 		return nil, err
 	}
 
+	if confirmations.Enabled() {
+		if txResult, err = confirmations.Result(params.FromShardID, confirmations.Staking, txResult, params.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	transactions.Gas.Record(txResult, gasLimit, gasPrice)
+
 	return txResult, nil
 }
 
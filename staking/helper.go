@@ -8,8 +8,11 @@ import (
 	"github.com/harmony-one/harmony-tf/config"
 	"github.com/harmony-one/harmony-tf/crypto"
 	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/metrics"
 	"github.com/harmony-one/harmony-tf/testing"
+	"github.com/harmony-one/harmony-tf/waits"
 	"github.com/harmony-one/harmony/numeric"
+	harmonyTypes "github.com/harmony-one/harmony/staking/types"
 
 	sdkAccounts "github.com/harmony-one/go-lib/accounts"
 	sdkCrypto "github.com/harmony-one/go-lib/crypto"
@@ -25,6 +28,21 @@ func ReuseOrCreateValidator(testCase *testing.TestCase, validatorName string) (a
 	}
 
 	validator = &testCase.StakingParameters.Create.Validator
+
+	if testCase.StakingParameters.ReuseExistingValidator && config.Configuration.Framework.ValidatorPool {
+		acquired, err := AcquirePooledValidator(validator)
+		if err != nil {
+			logger.ErrorLog(fmt.Sprintf("Failed to acquire a validator from the validator pool - error: %s", err.Error()), testCase.Verbose)
+		}
+
+		if acquired {
+			logger.StakingLog(fmt.Sprintf("Reusing the pooled validator %s", validator.Account.Address), testCase.Verbose)
+			testCase.StakingParameters.Create.Validator.Account = validator.Account
+			config.Configuration.Framework.CurrentValidator = validator
+			return validator.Account, validator, nil
+		}
+	}
+
 	acc, err := testing.GenerateAndFundAccount(testCase, validatorName, testCase.StakingParameters.Create.Validator.Amount, 1)
 	if err != nil {
 		return nil, nil, err
@@ -41,9 +59,7 @@ func ReuseOrCreateValidator(testCase *testing.TestCase, validatorName string) (a
 	testCase.Transactions = append(testCase.Transactions, tx)
 	testCase.StakingParameters.Create.Validator.BLSKeys = createdBlsKeys
 
-	if config.Configuration.Network.StakingWaitTime > 0 {
-		time.Sleep(time.Duration(config.Configuration.Network.StakingWaitTime) * time.Second)
-	}
+	WaitForStakingTransaction(testCase, tx)
 
 	validator.Exists = validatorExists
 	validator.BLSKeys = createdBlsKeys
@@ -61,11 +77,39 @@ func ReuseOrCreateValidator(testCase *testing.TestCase, validatorName string) (a
 	if testCase.StakingParameters.ReuseExistingValidator && config.Configuration.Framework.CurrentValidator == nil && validatorExists {
 		config.Configuration.Framework.CurrentValidator = validator
 		validator = config.Configuration.Framework.CurrentValidator
+
+		if config.Configuration.Framework.ValidatorPool {
+			if err := AddPooledValidator(validator, testCase.StakingParameters.Create.BLSSignatureMessage); err != nil {
+				logger.ErrorLog(fmt.Sprintf("Failed to add the validator %s to the validator pool - error: %s", validator.Account.Address, err.Error()), testCase.Verbose)
+			}
+		}
 	}
 
 	return account, validator, nil
 }
 
+// WaitForStakingTransaction - waits until a successful staking transaction has been included and confirmed by network.waits.staking_confirmations blocks
+// Sleeps for network.staking_wait_time seconds instead if confirmation waits are disabled
+func WaitForStakingTransaction(testCase *testing.TestCase, tx sdkTxs.Transaction) {
+	confirmations := config.Configuration.Network.Waits.StakingConfirmations
+	if confirmations == 0 {
+		if config.Configuration.Network.StakingWaitTime > 0 {
+			metrics.Sleep("staking wait time", time.Duration(config.Configuration.Network.StakingWaitTime)*time.Second)
+		}
+		return
+	}
+
+	// Rejected transactions never get included - there's no state change to wait for
+	if !tx.Success || tx.TransactionHash == "" {
+		return
+	}
+
+	logger.StakingLog(fmt.Sprintf("Waiting for staking transaction %s to be confirmed by %d block(s)", tx.TransactionHash, confirmations), testCase.Verbose)
+	if _, err := waits.Confirmations(0, tx.TransactionHash, confirmations, 0); err != nil {
+		logger.WarningLog(fmt.Sprintf("Failed to wait for the confirmation of staking transaction %s - error: %s", tx.TransactionHash, err.Error()), testCase.Verbose)
+	}
+}
+
 // BasicCreateValidator - helper method to create a validator
 func BasicCreateValidator(testCase *testing.TestCase, validatorAccount *sdkAccounts.Account, senderAccount *sdkAccounts.Account, blsKeys []sdkCrypto.BLSKey) (sdkTxs.Transaction, []sdkCrypto.BLSKey, bool, error) {
 	if senderAccount == nil {
@@ -81,6 +125,30 @@ func BasicCreateValidator(testCase *testing.TestCase, validatorAccount *sdkAccou
 		blsKeys = append(blsKeys, blsKeys[0])
 	case "amount_larger_than_balance", "amountlargerthanbalance":
 		testCase.StakingParameters.Create.Validator.Amount = testCase.StakingParameters.Create.Validator.Amount.Mul(numeric.NewDec(2))
+	case "amount_below_minimum_self_delegation", "amountbelowminimumselfdelegation":
+		testCase.StakingParameters.Create.Validator.Amount = testCase.StakingParameters.Create.Validator.MinimumSelfDelegation.Sub(numeric.SmallestDec())
+	case "wrong_shard_bls_key", "wrongshardblskey":
+		blsKey, err := crypto.GenerateBlsKeyForDifferentShard(testCase.StakingParameters.Create.Validator.ShardID, testCase.StakingParameters.Create.BLSSignatureMessage)
+		if err != nil {
+			return sdkTxs.Transaction{}, nil, false, err
+		}
+		blsKeys = replaceFirstBlsKey(blsKeys, blsKey)
+	case "wrong_message_bls_key", "wrongmessageblskey":
+		blsKey, err := crypto.GenerateBlsKeyWithWrongMessage(testCase.StakingParameters.Create.Validator.ShardID, testCase.StakingParameters.Create.BLSSignatureMessage)
+		if err != nil {
+			return sdkTxs.Transaction{}, nil, false, err
+		}
+		blsKeys = replaceFirstBlsKey(blsKeys, blsKey)
+	case "corrupted_bls_key_signature", "corruptedblskeysignature":
+		blsKey, err := crypto.GenerateBlsKeyWithCorruptedSignature(testCase.StakingParameters.Create.Validator.ShardID, testCase.StakingParameters.Create.BLSSignatureMessage)
+		if err != nil {
+			return sdkTxs.Transaction{}, nil, false, err
+		}
+		blsKeys = replaceFirstBlsKey(blsKeys, blsKey)
+	case "too_many_bls_keys", "toomanyblskeys":
+		if additionalKeyCount := harmonyTypes.MaxBLSPerValidator + 1 - len(blsKeys); additionalKeyCount > 0 {
+			blsKeys = append(blsKeys, crypto.GenerateBlsKeys(additionalKeyCount, testCase.StakingParameters.Create.Validator.ShardID, testCase.StakingParameters.Create.BLSSignatureMessage)...)
+		}
 	}
 
 	if len(blsKeys) > 0 {
@@ -222,7 +290,41 @@ func ManageBLSKeys(validator *sdkValidator.Validator, mode string, blsSignatureM
 		}
 		blsKeyToRemove = &nonExistingKey
 		logger.StakingLog(fmt.Sprintf("Removing non existing bls key %v from validator: %s", blsKeyToRemove.PublicKeyHex, validator.Account.Address), verbose)
+
+	case "add_wrong_shard_bls_key":
+		keyToAdd, err := crypto.GenerateBlsKeyForDifferentShard(validator.ShardID, blsSignatureMessage)
+		if err != nil {
+			return nil, nil, err
+		}
+		blsKeyToAdd = &keyToAdd
+		logger.StakingLog(fmt.Sprintf("Adding bls key %v belonging to a different shard than shard %d to validator: %s", blsKeyToAdd.PublicKeyHex, validator.ShardID, validator.Account.Address), verbose)
+
+	case "add_wrong_message_bls_key":
+		keyToAdd, err := crypto.GenerateBlsKeyWithWrongMessage(validator.ShardID, blsSignatureMessage)
+		if err != nil {
+			return nil, nil, err
+		}
+		blsKeyToAdd = &keyToAdd
+		logger.StakingLog(fmt.Sprintf("Adding bls key %v signed using the wrong message to validator: %s", blsKeyToAdd.PublicKeyHex, validator.Account.Address), verbose)
+
+	case "add_corrupted_bls_key_signature":
+		keyToAdd, err := crypto.GenerateBlsKeyWithCorruptedSignature(validator.ShardID, blsSignatureMessage)
+		if err != nil {
+			return nil, nil, err
+		}
+		blsKeyToAdd = &keyToAdd
+		logger.StakingLog(fmt.Sprintf("Adding bls key %v with a corrupted signature to validator: %s", blsKeyToAdd.PublicKeyHex, validator.Account.Address), verbose)
 	}
 
 	return blsKeyToRemove, blsKeyToAdd, nil
 }
+
+func replaceFirstBlsKey(blsKeys []sdkCrypto.BLSKey, blsKey sdkCrypto.BLSKey) []sdkCrypto.BLSKey {
+	if len(blsKeys) == 0 {
+		return []sdkCrypto.BLSKey{blsKey}
+	}
+
+	blsKeys[0] = blsKey
+
+	return blsKeys
+}
@@ -0,0 +1,63 @@
+package staking
+
+import (
+	sdkValidator "github.com/harmony-one/go-lib/staking/validator"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+// ValidatorInformation - retrieves the on-chain information for a given validator
+func ValidatorInformation(shardID uint32, validatorAddress string) (sdkValidator.RPCValidatorResult, error) {
+	node := config.Configuration.Network.API.NodeAddress(shardID)
+	return sdkValidator.Information(node, validatorAddress)
+}
+
+// DelegatedAmount - returns the amount a given delegator currently has delegated to the validator
+func DelegatedAmount(validatorInfo sdkValidator.RPCValidatorResult, delegatorAddress string) numeric.Dec {
+	for _, del := range validatorInfo.Validator.Delegations {
+		if del.DelegatorAddress == delegatorAddress && !del.Amount.IsNil() {
+			return del.Amount
+		}
+	}
+
+	return numeric.NewDec(0)
+}
+
+// SelfDelegation - returns the amount the validator has delegated to itself
+func SelfDelegation(validatorInfo sdkValidator.RPCValidatorResult) numeric.Dec {
+	return DelegatedAmount(validatorInfo, validatorInfo.Validator.Address)
+}
+
+// TotalDelegation - returns the validator's total delegation, treating a missing value as zero
+func TotalDelegation(validatorInfo sdkValidator.RPCValidatorResult) numeric.Dec {
+	if validatorInfo.TotalDelegation.IsNil() {
+		return numeric.NewDec(0)
+	}
+
+	return validatorInfo.TotalDelegation
+}
+
+// RemainingDelegationCapacity - calculates how much can still be delegated to a validator before it reaches its maximum total delegation
+func RemainingDelegationCapacity(validatorInfo sdkValidator.RPCValidatorResult) numeric.Dec {
+	if validatorInfo.Validator.MaxTotalDelegation.IsNil() {
+		return numeric.NewDec(0)
+	}
+
+	remaining := validatorInfo.Validator.MaxTotalDelegation.Sub(TotalDelegation(validatorInfo))
+	if remaining.IsNegative() {
+		return numeric.NewDec(0)
+	}
+
+	return remaining
+}
+
+// PendingUndelegations - returns the number of undelegations of a given delegator that haven't been returned to the delegator yet
+func PendingUndelegations(validatorInfo sdkValidator.RPCValidatorResult, delegatorAddress string) (pending int) {
+	for _, del := range validatorInfo.Validator.Delegations {
+		if del.DelegatorAddress == delegatorAddress {
+			pending += len(del.Undelegations)
+		}
+	}
+
+	return pending
+}
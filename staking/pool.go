@@ -0,0 +1,271 @@
+package staking
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	sdkAccounts "github.com/harmony-one/go-lib/accounts"
+	sdkCrypto "github.com/harmony-one/go-lib/crypto"
+	sdkValidator "github.com/harmony-one/go-lib/staking/validator"
+	sdkTxs "github.com/harmony-one/go-lib/transactions"
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/crypto"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/testing"
+	testParams "github.com/harmony-one/harmony-tf/testing/parameters"
+	"github.com/harmony-one/harmony-tf/utils"
+	"gopkg.in/yaml.v2"
+)
+
+var poolMutex sync.Mutex
+
+// ValidatorPool - represents the validators created by the framework that can be reused across runs
+type ValidatorPool struct {
+	Network    string            `yaml:"network"`
+	Validators []PooledValidator `yaml:"validators"`
+}
+
+// PooledValidator - represents a validator stored in the validator pool
+type PooledValidator struct {
+	Address             string         `yaml:"address"`
+	AccountName         string         `yaml:"account_name"`
+	ShardID             uint32         `yaml:"shard_id"`
+	BLSSignatureMessage string         `yaml:"bls_signature_message,omitempty"`
+	BLSKeys             []PooledBLSKey `yaml:"bls_keys"`
+	CreatedAt           time.Time      `yaml:"created_at"`
+	UndelegatedAt       *time.Time     `yaml:"undelegated_at,omitempty"`
+}
+
+// PooledBLSKey - represents a bls key belonging to a pooled validator
+type PooledBLSKey struct {
+	PrivateKey string `yaml:"private_key"`
+	PublicKey  string `yaml:"public_key"`
+}
+
+// ValidatorPoolPath - the path to the validator pool state file for the current network
+func ValidatorPoolPath() string {
+	return filepath.Join(config.Configuration.StatePath(), "validators.yml")
+}
+
+// LoadValidatorPool - loads the validator pool for the current network
+func LoadValidatorPool() (pool ValidatorPool, err error) {
+	if err = utils.ParseYaml(ValidatorPoolPath(), &pool); err != nil {
+		return ValidatorPool{}, err
+	}
+
+	if pool.Network == "" {
+		pool.Network = config.Configuration.Network.Name
+	}
+
+	return pool, nil
+}
+
+// Save - persists the validator pool to disk
+func (pool *ValidatorPool) Save() error {
+	path := ValidatorPoolPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(pool)
+	if err != nil {
+		return err
+	}
+
+	// The pool contains bls private keys - only the current user should be able to read it
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// Add - adds a validator to the pool
+func (pool *ValidatorPool) Add(validator *sdkValidator.Validator, blsSignatureMessage string) {
+	pooled := PooledValidator{
+		Address:             validator.Account.Address,
+		AccountName:         validator.Account.Name,
+		ShardID:             validator.ShardID,
+		BLSSignatureMessage: blsSignatureMessage,
+		CreatedAt:           time.Now().UTC(),
+	}
+
+	for _, blsKey := range validator.BLSKeys {
+		pooled.BLSKeys = append(pooled.BLSKeys, PooledBLSKey{PrivateKey: blsKey.PrivateKeyHex, PublicKey: blsKey.PublicKeyHex})
+	}
+
+	pool.Remove(pooled.Address)
+	pool.Validators = append(pool.Validators, pooled)
+}
+
+// Remove - removes a validator from the pool
+func (pool *ValidatorPool) Remove(address string) {
+	validators := []PooledValidator{}
+	for _, pooled := range pool.Validators {
+		if pooled.Address != address {
+			validators = append(validators, pooled)
+		}
+	}
+	pool.Validators = validators
+}
+
+// Account - looks up the keystore account of a pooled validator
+func (pooled *PooledValidator) Account() (*sdkAccounts.Account, error) {
+	account := sdkAccounts.FindAccountByName(pooled.AccountName)
+	if account.Address == "" || account.Address != pooled.Address {
+		return nil, fmt.Errorf("couldn't find the keystore account %s for the pooled validator %s", pooled.AccountName, pooled.Address)
+	}
+	account.Passphrase = config.Configuration.Account.Passphrase
+
+	return &account, nil
+}
+
+// RestoreBLSKeys - restores the bls keys of a pooled validator
+func (pooled *PooledValidator) RestoreBLSKeys() (blsKeys []sdkCrypto.BLSKey, err error) {
+	for _, pooledKey := range pooled.BLSKeys {
+		blsKey, err := crypto.RestoreBlsKey(pooledKey.PrivateKey, pooled.BLSSignatureMessage)
+		if err != nil {
+			return nil, err
+		}
+		blsKeys = append(blsKeys, blsKey)
+	}
+
+	return blsKeys, nil
+}
+
+// AcquirePooledValidator - finds a pooled validator in the given shard that still exists on chain and is still usable by the framework
+// Validators that no longer exist on chain or whose keystore accounts have been removed are removed from the pool
+func AcquirePooledValidator(validator *sdkValidator.Validator) (bool, error) {
+	poolMutex.Lock()
+	defer poolMutex.Unlock()
+
+	pool, err := LoadValidatorPool()
+	if err != nil {
+		return false, err
+	}
+
+	rpcClient, err := config.Configuration.Network.API.RPCClient(validator.ShardID)
+	if err != nil {
+		return false, err
+	}
+
+	acquired := false
+	stale := []string{}
+
+	for i := range pool.Validators {
+		pooled := &pool.Validators[i]
+		if pooled.ShardID != validator.ShardID || pooled.UndelegatedAt != nil {
+			continue
+		}
+
+		if !sdkValidator.Exists(rpcClient, pooled.Address) {
+			stale = append(stale, pooled.Address)
+			continue
+		}
+
+		account, err := pooled.Account()
+		if err != nil {
+			stale = append(stale, pooled.Address)
+			continue
+		}
+
+		blsKeys, err := pooled.RestoreBLSKeys()
+		if err != nil {
+			stale = append(stale, pooled.Address)
+			continue
+		}
+
+		validator.Account = account
+		validator.BLSKeys = blsKeys
+		validator.Exists = true
+		acquired = true
+		break
+	}
+
+	if len(stale) > 0 {
+		for _, address := range stale {
+			pool.Remove(address)
+		}
+
+		if err := pool.Save(); err != nil {
+			return acquired, err
+		}
+	}
+
+	return acquired, nil
+}
+
+// AddPooledValidator - adds a newly created validator to the validator pool
+func AddPooledValidator(validator *sdkValidator.Validator, blsSignatureMessage string) error {
+	// Pooled validators are reused across runs - in-memory accounts have to be persisted to the keystore
+	if err := accounts.Persist(validator.Account); err != nil {
+		return err
+	}
+
+	poolMutex.Lock()
+	defer poolMutex.Unlock()
+
+	pool, err := LoadValidatorPool()
+	if err != nil {
+		return err
+	}
+
+	pool.Add(validator, blsSignatureMessage)
+
+	return pool.Save()
+}
+
+// Prune - reclaims the stake of a pooled validator
+// The first prune undelegates the validator's self delegation, subsequent prunes will return the funds to the funding account and remove the account once the undelegated stake has been unlocked
+func (pooled *PooledValidator) Prune(verbose bool) (removed bool, err error) {
+	account, err := pooled.Account()
+	if err != nil {
+		return false, err
+	}
+
+	validatorInfo, err := ValidatorInformation(pooled.ShardID, pooled.Address)
+	if err != nil {
+		return false, err
+	}
+
+	selfDelegation := SelfDelegation(validatorInfo)
+	if selfDelegation.IsPositive() {
+		params := testParams.StakingParameters{
+			FromShardID: pooled.ShardID,
+			ToShardID:   pooled.ShardID,
+			Nonce:       -1,
+			Timeout:     config.Configuration.Funding.Timeout,
+		}
+		params.Delegation.Undelegate.Amount = selfDelegation
+		params.Delegation.Undelegate.Gas = config.Configuration.Network.Gas
+
+		logger.StakingLog(fmt.Sprintf("Undelegating the self delegation of %f from the pooled validator %s", selfDelegation, pooled.Address), verbose)
+		rawTx, err := Undelegate(account, account, nil, &params)
+		if err != nil {
+			return false, err
+		}
+
+		// A rejected undelegation leaves the stake locked - the validator must stay available and be pruned again later
+		if tx := sdkTxs.ToTransaction(account.Address, pooled.ShardID, account.Address, pooled.ShardID, rawTx, err); !tx.Success {
+			return false, fmt.Errorf("the undelegation of the self delegation of %f from the pooled validator %s wasn't successful", selfDelegation, pooled.Address)
+		}
+
+		undelegatedAt := time.Now().UTC()
+		pooled.UndelegatedAt = &undelegatedAt
+
+		return false, nil
+	}
+
+	if pending := PendingUndelegations(validatorInfo, pooled.Address); pending > 0 {
+		logger.StakingLog(fmt.Sprintf("The pooled validator %s still has %d pending undelegation(s) - the stake can be reclaimed once they've been unlocked", pooled.Address, pending), verbose)
+		return false, nil
+	}
+
+	logger.TeardownLog(fmt.Sprintf("Returning the funds of the pooled validator %s to the funding account and removing the account %s", pooled.Address, pooled.AccountName), verbose)
+	if err := testing.Teardown(account, config.Configuration.Funding.Account.Address); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
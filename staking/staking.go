@@ -8,7 +8,10 @@ import (
 	sdkNetworkNonce "github.com/harmony-one/go-lib/network/rpc/nonces"
 	sdkValidator "github.com/harmony-one/go-lib/staking/validator"
 	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/confirmations"
+	"github.com/harmony-one/harmony-tf/snapshots"
 	testParams "github.com/harmony-one/harmony-tf/testing/parameters"
+	"github.com/harmony-one/harmony-tf/transactions"
 	"github.com/harmony-one/harmony/numeric"
 )
 
@@ -48,11 +51,13 @@ func CreateValidator(validatorAccount *sdkAccounts.Account, senderAccount *sdkAc
 		currentNonce = uint64(params.Nonce)
 	}
 
+	snapshots.Stake(senderAccount.Address, senderAccount.Name, params.FromShardID, validatorAccount.Address, validatorAccount.Address, params.Create.Validator.Amount, params.Gas.Limit, params.Gas.Price)
+
 	txResult, err := sdkValidator.Create(
 		senderAccount.Keystore,
 		senderAccount.Account,
 		rpcClient,
-		config.Configuration.Network.API.ChainID,
+		config.Configuration.Network.StakingChainID,
 		validatorAccount.Address,
 		params.Create.Validator.ToStakingDescription(),
 		params.Create.Validator.ToCommissionRates(),
@@ -65,13 +70,21 @@ func CreateValidator(validatorAccount *sdkAccounts.Account, senderAccount *sdkAc
 		currentNonce,
 		config.Configuration.Account.Passphrase,
 		config.Configuration.Network.API.NodeAddress(params.FromShardID),
-		params.Timeout,
+		confirmations.SendTimeout(params.Timeout),
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if confirmations.Enabled() {
+		if txResult, err = confirmations.Result(params.FromShardID, confirmations.Staking, txResult, params.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	transactions.Gas.Record(txResult, params.Gas.Limit, params.Gas.Price)
+
 	return txResult, nil
 }
 
@@ -114,11 +127,13 @@ func EditValidator(validatorAccount *sdkAccounts.Account, senderAccount *sdkAcco
 		gasPrice = params.Edit.Gas.Price
 	}
 
+	snapshots.Stake(senderAccount.Address, senderAccount.Name, params.FromShardID, validatorAccount.Address, "", numeric.NewDec(0), gasLimit, gasPrice)
+
 	txResult, err := sdkValidator.Edit(
 		senderAccount.Keystore,
 		senderAccount.Account,
 		rpcClient,
-		config.Configuration.Network.API.ChainID,
+		config.Configuration.Network.StakingChainID,
 		validatorAccount.Address,
 		params.Edit.Validator.ToStakingDescription(),
 		commissionRate,
@@ -132,13 +147,21 @@ func EditValidator(validatorAccount *sdkAccounts.Account, senderAccount *sdkAcco
 		currentNonce,
 		config.Configuration.Account.Passphrase,
 		config.Configuration.Network.API.NodeAddress(params.FromShardID),
-		params.Timeout,
+		confirmations.SendTimeout(params.Timeout),
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if confirmations.Enabled() {
+		if txResult, err = confirmations.Result(params.FromShardID, confirmations.Staking, txResult, params.Timeout); err != nil {
+			return nil, err
+		}
+	}
+
+	transactions.Gas.Record(txResult, gasLimit, gasPrice)
+
 	return txResult, nil
 }
 
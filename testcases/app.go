@@ -6,16 +6,24 @@ import (
 	"time"
 
 	"github.com/gookit/color"
+	_ "github.com/harmony-one/harmony-tf/commands" // registers the framework's sub commands
 	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/confirmations"
+	"github.com/harmony-one/harmony-tf/conservation"
 	"github.com/harmony-one/harmony-tf/export"
 	"github.com/harmony-one/harmony-tf/funding"
 	"github.com/harmony-one/harmony-tf/keys"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/metrics"
+	"github.com/harmony-one/harmony-tf/network"
 	stakingDelegationDelegateScenarios "github.com/harmony-one/harmony-tf/scenarios/staking/delegation/delegate"
 	stakingDelegationUndelegateScenarios "github.com/harmony-one/harmony-tf/scenarios/staking/delegation/undelegate"
 	stakingCreateValidatorScenarios "github.com/harmony-one/harmony-tf/scenarios/staking/validator/create"
 	stakingEditValidatorScenarios "github.com/harmony-one/harmony-tf/scenarios/staking/validator/edit"
 	transactionScenarios "github.com/harmony-one/harmony-tf/scenarios/transactions"
+	"github.com/harmony-one/harmony-tf/snapshots"
 	"github.com/harmony-one/harmony-tf/testing"
+	"github.com/harmony-one/harmony-tf/utils"
 )
 
 var (
@@ -41,22 +49,33 @@ func Execute() error {
 	}
 
 	if len(TestCases) > 0 {
+		watchInterrupts()
+
 		execute()
-		successfulCount, failedCount, duration := results()
+		close(executorDone)
+		if interrupted() {
+			shutdown(true)
+		}
 
-		switch strings.ToLower(config.Configuration.Export.Format) {
-		case "csv":
-			csvPath, err := export.ExportCSV(Results, Dismissed, Failed, successfulCount, failedCount, duration)
-			if err != nil {
-				fmt.Println("Failed to export test case results to CSV")
-			} else if csvPath != "" {
-				fmt.Printf("Successfully exported test case results to %s\n", csvPath)
-			}
-		//case "json":
-		default:
+		funding.Ledger.SetTestCase("")
+		useCassette(network.TeardownCassette)
+		funding.ReclaimSubFunders()
+		conservation.Finish()
+		successfulCount, failedCount, duration := results()
+		fundingReport()
+		conservationReport()
+		metricsReport()
+		exportResults(config.Configuration.Export.Format, successfulCount, failedCount, duration)
+		confirmations.Close()
+		if err := network.Stop(); err != nil {
+			logger.WarningLog(fmt.Sprintf("Failed to save the recorded cassette - error: %s", err.Error()), true)
 		}
 
 		footer()
+
+		if conservation.Suite.Failed() {
+			return fmt.Errorf("framework error: %f of the funds held by the funding account and the source keys can't be accounted for", conservation.Suite.Unexplained())
+		}
 	} else {
 		fmt.Println(fmt.Sprintf("Couldn't find any test cases - are you sure you've placed them in the testcases folder?"))
 	}
@@ -64,6 +83,20 @@ func Execute() error {
 	return nil
 }
 
+func exportResults(format string, successfulCount int, failedCount int, duration time.Duration) {
+	switch strings.ToLower(format) {
+	case "csv":
+		csvPath, err := export.ExportCSV(Results, Dismissed, Failed, successfulCount, failedCount, duration)
+		if err != nil {
+			fmt.Println("Failed to export test case results to CSV")
+		} else if csvPath != "" {
+			fmt.Printf("Successfully exported test case results to %s\n", csvPath)
+		}
+	//case "json":
+	default:
+	}
+}
+
 func header() {
 	fmt.Println()
 	config.Configuration.Framework.Styling.Header.Println(
@@ -91,6 +124,10 @@ func prepare() (err error) {
 		return err
 	}
 
+	if err = network.Setup(); err != nil {
+		return err
+	}
+
 	accs, err := keys.LoadKeys()
 	if err != nil {
 		return err
@@ -100,12 +137,31 @@ func prepare() (err error) {
 		return err
 	}
 
+	conservation.Begin(accs)
+
 	return nil
 }
 
 func execute() {
 	for _, testCase := range TestCases {
 		if testCase.Execute {
+			if interrupted() {
+				testCase.Dismissal = "Test suite was interrupted"
+				dismiss(testCase)
+				continue
+			}
+
+			if funding.Ledger.BudgetExhausted() {
+				testCase.ReportBudgetDismissal()
+				dismiss(testCase)
+				continue
+			}
+
+			funding.Ledger.SetTestCase(testCase.Name)
+			useCassette(testCase.Name)
+
+			snapshots.Start(testCase.Name)
+
 			switch testCase.Scenario {
 			case "transactions/standard":
 				transactionScenarios.StandardScenario(testCase)
@@ -123,36 +179,48 @@ func execute() {
 				stakingCreateValidatorScenarios.AlreadyExistsScenario(testCase)
 			case "staking/validator/create/existing_bls_key":
 				stakingCreateValidatorScenarios.ExistingBLSKeyScenario(testCase)
+			case "staking/validator/create/description_boundaries":
+				stakingCreateValidatorScenarios.DescriptionBoundariesScenario(testCase)
 			case "staking/validator/edit/standard":
 				stakingEditValidatorScenarios.StandardScenario(testCase)
 			case "staking/validator/edit/invalid_address":
 				stakingEditValidatorScenarios.InvalidAddressScenario(testCase)
 			case "staking/validator/edit/non_existing":
 				stakingEditValidatorScenarios.NonExistingScenario(testCase)
+			case "staking/validator/edit/description_boundaries":
+				stakingEditValidatorScenarios.DescriptionBoundariesScenario(testCase)
+			case "staking/validator/edit/invalid_bls_key":
+				stakingEditValidatorScenarios.InvalidBLSKeyScenario(testCase)
 			case "staking/delegation/delegate/standard":
 				stakingDelegationDelegateScenarios.StandardScenario(testCase)
 			case "staking/delegation/delegate/invalid_address":
 				stakingDelegationDelegateScenarios.InvalidAddressScenario(testCase)
 			case "staking/delegation/delegate/non_existing":
 				stakingDelegationDelegateScenarios.NonExistingScenario(testCase)
+			case "staking/delegation/delegate/max_total_delegation":
+				stakingDelegationDelegateScenarios.MaxTotalDelegationScenario(testCase)
+			case "staking/delegation/delegate/multiple_delegators":
+				stakingDelegationDelegateScenarios.MultipleDelegatorsScenario(testCase)
 			case "staking/delegation/undelegate/standard":
 				stakingDelegationUndelegateScenarios.StandardScenario(testCase)
 			case "staking/delegation/undelegate/invalid_address":
 				stakingDelegationUndelegateScenarios.InvalidAddressScenario(testCase)
 			case "staking/delegation/undelegate/non_existing":
 				stakingDelegationUndelegateScenarios.NonExistingScenario(testCase)
+			case "staking/delegation/undelegate/below_minimum_self_delegation":
+				stakingDelegationUndelegateScenarios.BelowMinimumSelfDelegationScenario(testCase)
 			default:
 				testCase.Executed = false
 				fmt.Println(fmt.Sprintf("Please specify a valid test type for your test case %s", testCase.Name))
 			}
 
+			testCase.Warnings = append(testCase.Warnings, testing.CollectTeardownWarnings()...)
+			snapshot(testCase)
+
 			if testCase.Executed {
-				Results = append(Results, testCase)
-				if !testCase.Successful() {
-					Failed = append(Failed, testCase)
-				}
+				record(testCase)
 			} else {
-				Dismissed = append(Dismissed, testCase)
+				dismiss(testCase)
 			}
 		} else {
 			fmt.Println(fmt.Sprintf("\nTest case %s has the execute attribute set to false - make sure to set it to true if you want to execute this test case\n", testCase.Name))
@@ -160,6 +228,25 @@ func execute() {
 	}
 }
 
+// record - records the result of an executed test case
+func record(testCase *testing.TestCase) {
+	resultsMutex.Lock()
+	defer resultsMutex.Unlock()
+
+	Results = append(Results, testCase)
+	if !testCase.Successful() {
+		Failed = append(Failed, testCase)
+	}
+}
+
+// dismiss - records a dismissed test case
+func dismiss(testCase *testing.TestCase) {
+	resultsMutex.Lock()
+	defer resultsMutex.Unlock()
+
+	Dismissed = append(Dismissed, testCase)
+}
+
 func results() (successfulCount int, failedCount int, duration time.Duration) {
 	config.Configuration.Framework.EndTime = time.Now().UTC()
 	duration = config.Configuration.Framework.EndTime.Sub(config.Configuration.Framework.StartTime)
@@ -207,6 +294,24 @@ func results() (successfulCount int, failedCount int, duration time.Duration) {
 		fmt.Println("")
 	}
 
+	warningCount := 0
+	for _, testCase := range TestCases {
+		warningCount += len(testCase.Warnings)
+	}
+
+	if warningCount > 0 {
+		fmt.Println("")
+		color.Style{color.OpBold}.Println("Teardown warnings:")
+		fmt.Println(strings.Repeat("-", 50))
+		for _, testCase := range TestCases {
+			for _, warning := range testCase.Warnings {
+				fmt.Println(fmt.Sprintf("%s %s", color.Style{color.OpItalic}.Sprintf("Testcase %s:", testCase.Name), config.Configuration.Framework.Styling.Warning.Render(warning)))
+			}
+		}
+		fmt.Println(strings.Repeat("-", 50))
+		fmt.Println("")
+	}
+
 	if len(Dismissed) > 0 {
 		fmt.Println("")
 		color.Style{color.OpBold}.Println("Test cases that weren't executed/were dismissed:")
@@ -223,6 +328,81 @@ func results() (successfulCount int, failedCount int, duration time.Duration) {
 	return successfulCount, failedCount, duration
 }
 
+// snapshot - compares the state of all accounts touched by a test case before and after it and reports unexpected changes
+func snapshot(testCase *testing.TestCase) {
+	torndown := []string{}
+	for _, address := range funding.Ledger.FundedAccounts(testCase.Name) {
+		if !utils.StringSliceContains(keptAddresses(), address) {
+			torndown = append(torndown, address)
+		}
+	}
+
+	testCase.Snapshot = snapshots.Finish(torndown)
+	if testCase.Snapshot == nil {
+		return
+	}
+
+	changes, unexpected := testCase.Snapshot.Changes()
+	if unexpected > 0 {
+		logger.WarningLog(fmt.Sprintf("Test case %s caused %d unexpected state change(s):", testCase.Name, unexpected), true)
+		fmt.Print(testCase.Snapshot.String())
+	} else {
+		logger.Log(fmt.Sprintf("Test case %s caused %d expected state change(s) in %d account(s)", testCase.Name, changes, len(testCase.Snapshot.Accounts)), testCase.Verbose)
+		if testCase.Verbose {
+			fmt.Print(testCase.Snapshot.String())
+		}
+	}
+}
+
+// keptAddresses - generated accounts that are deliberately kept after their test case, e.g. reused validators
+func keptAddresses() []string {
+	excludedAddresses := []string{}
+	if config.Configuration.Framework.CurrentValidator != nil && config.Configuration.Framework.CurrentValidator.Account != nil {
+		excludedAddresses = append(excludedAddresses, config.Configuration.Framework.CurrentValidator.Account.Address)
+	}
+
+	return excludedAddresses
+}
+
+func fundingReport() {
+	fmt.Println("")
+	color.Style{color.OpBold}.Println("Funding costs:")
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Print(funding.Ledger.Report(keptAddresses()))
+	fmt.Println("")
+}
+
+// conservationReport - reports the fund conservation check - unexplained losses are framework errors rather than test case failures
+func conservationReport() {
+	if !conservation.Suite.Finished() {
+		return
+	}
+
+	fmt.Println("")
+	color.Style{color.OpBold}.Println("Fund conservation:")
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Print(conservation.Suite.Report())
+
+	if conservation.Suite.Failed() {
+		logger.ErrorLog(fmt.Sprintf("Framework error: %f of the funds held by the funding account and the source keys can't be explained by gas, locked stake or stranded funds", conservation.Suite.Unexplained()), true)
+	} else if !conservation.Suite.Completed() {
+		logger.WarningLog("The fund conservation check couldn't be completed since some balances couldn't be retrieved", true)
+	}
+	fmt.Println("")
+}
+
+func metricsReport() {
+	fmt.Println("")
+	color.Style{color.OpBold}.Println("RPC calls:")
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Print(metrics.RPC.Report())
+	fmt.Println("")
+	color.Style{color.OpBold}.Println("Framework sleeps:")
+	fmt.Println(strings.Repeat("-", 50))
+	fmt.Print(metrics.Sleeps.Report())
+	fmt.Println("")
+}
+
 func footer() {
 	fmt.Println("")
 	color.Style{color.FgBlack, color.BgWhite, color.OpBold}.Println(
@@ -233,3 +413,10 @@ func footer() {
 		),
 	)
 }
+
+// useCassette - switches the recorder/replayer to the cassette of a given test case
+func useCassette(name string) {
+	if err := network.Recorder.SetTestCase(name); err != nil {
+		logger.WarningLog(fmt.Sprintf("Failed to switch to the cassette for test case %s - error: %s", name, err.Error()), true)
+	}
+}
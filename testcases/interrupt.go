@@ -0,0 +1,134 @@
+package testcases
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	sdkAccounts "github.com/harmony-one/go-lib/accounts"
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/confirmations"
+	"github.com/harmony-one/harmony-tf/conservation"
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/network"
+	"github.com/harmony-one/harmony-tf/testing"
+)
+
+const (
+	// ExitCodeInterrupted - the exit status used when the test suite was interrupted by SIGINT/SIGTERM
+	ExitCodeInterrupted = 130
+)
+
+var (
+	interruptedFlag int32
+	shutdownOnce    sync.Once
+
+	// executorDone - closed once the executor has stopped, i.e. once no test case is running and no further results will be recorded
+	executorDone = make(chan struct{})
+
+	// resultsMutex - guards Results, Dismissed and Failed while a forced shutdown runs next to a still running test case
+	resultsMutex sync.Mutex
+)
+
+// watchInterrupts - listens for SIGINT/SIGTERM - once received no new test cases will be started
+// The executor stops after the current test case and Execute shuts down - if the current test case doesn't finish within the grace period all generated accounts are torn down regardless
+func watchInterrupts() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		sig := <-signals
+		atomic.StoreInt32(&interruptedFlag, 1)
+
+		gracePeriod := interruptGracePeriod()
+		logger.WarningLog(fmt.Sprintf("Received %s - no new test cases will be started. Waiting up to %v for the current test case's transactions to finish before tearing down all generated accounts (send the signal again to tear down immediately)", sig, gracePeriod), true)
+
+		select {
+		case <-executorDone:
+			// The executor has drained - Execute performs the shutdown using the complete results
+			return
+		case <-signals:
+		case <-time.After(gracePeriod):
+		}
+
+		logger.WarningLog("The current test case is still running - tearing down all generated accounts regardless", true)
+		shutdown(false)
+	}()
+}
+
+func interrupted() bool {
+	return atomic.LoadInt32(&interruptedFlag) == 1
+}
+
+func interruptGracePeriod() time.Duration {
+	timeout := config.Configuration.Network.Timeout
+	if config.Configuration.Funding.Timeout > timeout {
+		timeout = config.Configuration.Funding.Timeout
+	}
+	if timeout <= 0 {
+		timeout = 60
+	}
+
+	return time.Duration(timeout) * time.Second
+}
+
+// shutdown - tears down all live generated accounts in parallel, writes a partial export and exits
+// drained signals whether or not the executor has stopped - if it hasn't (forced shutdown) it's blocked from recording further results and the fund conservation check is skipped since transactions are still in flight
+func shutdown(drained bool) {
+	shutdownOnce.Do(func() {
+		// Never released - the process exits at the end of the shutdown
+		resultsMutex.Lock()
+
+		useCassette(network.TeardownCassette)
+		liveAccounts := interruptTeardownAccounts()
+		logger.TeardownLog(fmt.Sprintf("Tearing down a total of %d generated account(s)", len(liveAccounts)), true)
+
+		var waitGroup sync.WaitGroup
+		for i := range liveAccounts {
+			waitGroup.Add(1)
+			go testing.AsyncTeardown(&liveAccounts[i], config.Configuration.Funding.Account.Address, &waitGroup)
+		}
+		waitGroup.Wait()
+
+		funding.Ledger.SetTestCase("")
+		funding.ReclaimSubFunders()
+		if drained {
+			conservation.Finish()
+		} else {
+			logger.WarningLog("Skipping the fund conservation check since the interrupted test case still had transactions in flight", true)
+		}
+		successfulCount, failedCount, duration := results()
+		fundingReport()
+		conservationReport()
+
+		exportResults("csv", successfulCount, failedCount, duration)
+		confirmations.Close()
+		network.Stop()
+
+		os.Exit(ExitCodeInterrupted)
+	})
+}
+
+// interruptTeardownAccounts - the live accounts that should be torn down - the funding account and pooled validators are always kept
+func interruptTeardownAccounts() (accs []sdkAccounts.Account) {
+	for _, account := range accounts.LiveAccounts() {
+		if account.Address == config.Configuration.Funding.Account.Address {
+			continue
+		}
+
+		currentValidator := config.Configuration.Framework.CurrentValidator
+		if config.Configuration.Framework.ValidatorPool && currentValidator != nil && currentValidator.Account != nil && currentValidator.Account.Address == account.Address {
+			continue
+		}
+
+		accs = append(accs, account)
+	}
+
+	return accs
+}
@@ -1,6 +1,7 @@
 package testing
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/harmony-one/harmony-tf/accounts"
@@ -41,7 +42,7 @@ func GenerateAndFundAccount(testCase *TestCase, accountName string, amount numer
 	gasLimit := -1
 
 	if accountStartingBalance.LT(fundingAmount) {
-		funding.PerformFundingTransaction(
+		err = funding.PerformFundingTransaction(
 			&config.Configuration.Funding.Account,
 			testCase.Parameters.FromShardID,
 			account.Address,
@@ -53,6 +54,10 @@ func GenerateAndFundAccount(testCase *TestCase, accountName string, amount numer
 			config.Configuration.Funding.Timeout,
 			config.Configuration.Funding.Retry.Attempts,
 		)
+		if errors.Is(err, funding.ErrBudgetExceeded) {
+			return account, err
+		}
+
 		accountStartingBalance, err = balances.GetShardBalance(account.Address, testCase.StakingParameters.FromShardID)
 		if err != nil {
 			return sdkAccounts.Account{}, err
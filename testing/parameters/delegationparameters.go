@@ -13,6 +13,9 @@ type DelegationParameters struct {
 	RawAmount string      `yaml:"amount"`
 	Amount    numeric.Dec `yaml:"-"`
 
+	// The number of delegators to use for scenarios delegating from multiple accounts at the same time
+	DelegatorCount int64 `yaml:"delegator_count"`
+
 	Delegate   DelegationInstruction `yaml:"delegate"`
 	Undelegate DelegationInstruction `yaml:"undelegate"`
 }
@@ -26,6 +29,10 @@ type DelegationInstruction struct {
 
 // Initialize - initializes the edit staking parameters
 func (delegationParams *DelegationParameters) Initialize() error {
+	if delegationParams.DelegatorCount <= 0 {
+		delegationParams.DelegatorCount = 1
+	}
+
 	if delegationParams.RawAmount != "" {
 		decAmount, err := common.NewDecFromString(delegationParams.RawAmount)
 		if err != nil {
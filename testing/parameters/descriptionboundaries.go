@@ -0,0 +1,182 @@
+package parameters
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	sdkValidator "github.com/harmony-one/go-lib/staking/validator"
+	"github.com/harmony-one/harmony-tf/utils"
+	harmonyTypes "github.com/harmony-one/harmony/staking/types"
+)
+
+var (
+	multiByteFiller = "é"
+	asciiFiller     = "a"
+)
+
+// DescriptionBoundaryCase - represents a validator description payload testing the length boundary of a specific field
+type DescriptionBoundaryCase struct {
+	Field     string
+	Variant   string
+	MaxLength int
+	Details   sdkValidator.ValidatorDetails
+	Expected  bool
+	Result    bool
+	Error     error
+}
+
+// Value - the value used for the field under test
+func (boundaryCase *DescriptionBoundaryCase) Value() string {
+	switch boundaryCase.Field {
+	case "name":
+		return boundaryCase.Details.Name
+	case "identity":
+		return boundaryCase.Details.Identity
+	case "website":
+		return boundaryCase.Details.Website
+	case "security_contact":
+		return boundaryCase.Details.SecurityContact
+	case "details":
+		return boundaryCase.Details.Details
+	}
+
+	return ""
+}
+
+// Successful - if the case was accepted/rejected as expected
+func (boundaryCase *DescriptionBoundaryCase) Successful() bool {
+	return boundaryCase.Result == boundaryCase.Expected
+}
+
+// GenerateDescriptionBoundaryCases - generates validator description payloads with every description field at, one under and one over its maximum length, using single and multi byte UTF-8 values as well as empty values
+// If a base is supplied the other fields will retain the base values (with a unique identity), otherwise they will be left empty
+func GenerateDescriptionBoundaryCases(base *sdkValidator.ValidatorDetails) (cases []DescriptionBoundaryCase) {
+	fields := []struct {
+		name      string
+		maxLength int
+	}{
+		{"name", harmonyTypes.MaxNameLength},
+		{"identity", harmonyTypes.MaxIdentityLength},
+		{"website", harmonyTypes.MaxWebsiteLength},
+		{"security_contact", harmonyTypes.MaxSecurityContactLength},
+		{"details", harmonyTypes.MaxDetailsLength},
+	}
+
+	for _, field := range fields {
+		variants := []struct {
+			name      string
+			length    int
+			multiByte bool
+			expected  bool
+		}{
+			{"empty", 0, false, true},
+			{"one_under_max", field.maxLength - 1, false, true},
+			{"at_max", field.maxLength, false, true},
+			{"one_over_max", field.maxLength + 1, false, false},
+			{"multi_byte_at_max", field.maxLength, true, true},
+			{"multi_byte_over_max", field.maxLength + 1, true, false},
+		}
+
+		for _, variant := range variants {
+			details := sdkValidator.ValidatorDetails{}
+			if base != nil {
+				details = *base
+				details.Identity = generateUniqueProperty(base.Identity, harmonyTypes.MaxIdentityLength)
+			}
+
+			value := boundaryValue(variant.length, variant.multiByte)
+			setDescriptionField(&details, field.name, value)
+
+			cases = append(cases, DescriptionBoundaryCase{
+				Field:     field.name,
+				Variant:   variant.name,
+				MaxLength: field.maxLength,
+				Details:   details,
+				Expected:  variant.expected,
+			})
+		}
+	}
+
+	return cases
+}
+
+// DescriptionBoundaryTable - formats the results of a set of description boundary cases as a table
+func DescriptionBoundaryTable(cases []DescriptionBoundaryCase) string {
+	var builder strings.Builder
+	format := "%-18s %-21s %-6s %-6s %-9s %-9s %s\n"
+
+	builder.WriteString(fmt.Sprintf(format, "Field", "Variant", "Bytes", "Runes", "Expected", "Result", "Status"))
+	builder.WriteString(fmt.Sprintf("%s\n", strings.Repeat("-", 85)))
+
+	for _, boundaryCase := range cases {
+		value := boundaryCase.Value()
+		status := "Success"
+		if !boundaryCase.Successful() {
+			status = "Failed"
+		}
+
+		builder.WriteString(fmt.Sprintf(format,
+			boundaryCase.Field,
+			boundaryCase.Variant,
+			fmt.Sprintf("%d", len(value)),
+			fmt.Sprintf("%d", len([]rune(value))),
+			acceptanceMessage(boundaryCase.Expected),
+			acceptanceMessage(boundaryCase.Result),
+			status,
+		))
+	}
+
+	return builder.String()
+}
+
+func acceptanceMessage(accepted bool) string {
+	if accepted {
+		return "accepted"
+	}
+
+	return "rejected"
+}
+
+// boundaryValue - generates a unique value of exactly byteLength bytes - multi byte values use as many two byte runes as possible
+func boundaryValue(byteLength int, multiByte bool) string {
+	if byteLength <= 0 {
+		return ""
+	}
+
+	prefix := fmt.Sprintf("%s-%d-", utils.FormattedTimeString(time.Now().UTC()), rand.Intn(1000))
+	if len(prefix) > byteLength {
+		prefix = prefix[0:byteLength]
+	}
+
+	var builder strings.Builder
+	builder.WriteString(prefix)
+
+	if multiByte {
+		for builder.Len()+len(multiByteFiller) <= byteLength {
+			builder.WriteString(multiByteFiller)
+		}
+	}
+
+	for builder.Len() < byteLength {
+		builder.WriteString(asciiFiller)
+	}
+
+	return builder.String()
+}
+
+func setDescriptionField(details *sdkValidator.ValidatorDetails, field string, value string) {
+	switch field {
+	case "name":
+		details.Name = value
+	case "identity":
+		details.Identity = value
+	case "website":
+		details.Website = value
+	case "security_contact":
+		details.SecurityContact = value
+	case "details":
+		details.Details = value
+	}
+}
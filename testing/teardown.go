@@ -1,35 +1,61 @@
 package testing
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 
 	sdkAccounts "github.com/harmony-one/go-lib/accounts"
-	goSdkAccount "github.com/harmony-one/go-sdk/pkg/account"
-	"github.com/harmony-one/harmony-tf/balances"
-	"github.com/harmony-one/harmony-tf/config"
-	"github.com/harmony-one/harmony-tf/transactions"
-	"github.com/harmony-one/harmony/numeric"
+	"github.com/harmony-one/harmony-tf/accounts"
+	"github.com/harmony-one/harmony-tf/funding"
+	"github.com/harmony-one/harmony-tf/logger"
 )
 
-// Teardown - return any sent tokens (minus a gas cost) and remove the account from the keystore
-func Teardown(account *sdkAccounts.Account, fromShardID uint32, toAddress string, toShardID uint32) {
-	amount, err := balances.GetShardBalance(account.Address, fromShardID)
+var (
+	teardownWarningsMutex sync.Mutex
+	teardownWarnings      []string
+)
 
-	if err == nil && !amount.IsNil() {
-		if amount.GT(numeric.NewDec(0)) {
-			amount = amount.Sub(config.Configuration.Funding.Gas.Cost)
-		}
+// Teardown - return any sent tokens (minus a gas cost) held in any shard to the same shard of the receiver and remove the account from the keystore
+// The account is only removed from the keystore once its final balances have been verified - otherwise it's kept and a teardown warning is reported
+func Teardown(account *sdkAccounts.Account, toAddress string) error {
+	failures := funding.ReturnFunds(account, toAddress)
 
-		if amount.GT(numeric.NewDec(0)) {
-			transactions.SendTransaction(account, fromShardID, toAddress, toShardID, amount, -1, config.Configuration.Funding.Gas.Limit, config.Configuration.Funding.Gas.Price, "", 0)
+	if len(failures) > 0 {
+		err := fmt.Errorf("failed to tear down account %s, address: %s - keeping it in the keystore (%s)", account.Name, account.Address, strings.Join(failures, ", "))
+		if persistErr := accounts.Persist(account); persistErr != nil {
+			err = fmt.Errorf("%s - failed to persist the in-memory account to the keystore: %s", err.Error(), persistErr.Error())
 		}
+		addTeardownWarning(err.Error())
+		logger.WarningLog(err.Error(), true)
+		return err
 	}
 
-	goSdkAccount.RemoveAccount(account.Name)
+	accounts.RemoveAccount(account)
+
+	return nil
 }
 
 // AsyncTeardown - return any sent tokens (minus a gas cost) and remove the account from the keystore
-func AsyncTeardown(account *sdkAccounts.Account, fromShardID uint32, toAddress string, toShardID uint32, waitGroup *sync.WaitGroup) {
+func AsyncTeardown(account *sdkAccounts.Account, toAddress string, waitGroup *sync.WaitGroup) {
 	defer waitGroup.Done()
-	Teardown(account, fromShardID, toAddress, toShardID)
+	Teardown(account, toAddress)
+}
+
+// CollectTeardownWarnings - returns and clears the teardown warnings reported since the last collection
+func CollectTeardownWarnings() []string {
+	teardownWarningsMutex.Lock()
+	defer teardownWarningsMutex.Unlock()
+
+	warnings := teardownWarnings
+	teardownWarnings = nil
+
+	return warnings
+}
+
+func addTeardownWarning(warning string) {
+	teardownWarningsMutex.Lock()
+	defer teardownWarningsMutex.Unlock()
+
+	teardownWarnings = append(teardownWarnings, warning)
 }
@@ -8,7 +8,9 @@ import (
 	sdkAccounts "github.com/harmony-one/go-lib/accounts"
 	sdkTxs "github.com/harmony-one/go-lib/transactions"
 	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/funding"
 	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/snapshots"
 	"github.com/harmony-one/harmony-tf/testing/parameters"
 )
 
@@ -27,11 +29,13 @@ type TestCase struct {
 	Verbose           bool      `yaml:"verbose"`
 	Scenario          string    `yaml:"scenario"`
 	Dismissal         string    `yaml:"-"`
+	Warnings          []string  `yaml:"-"`
 	Error             error
 	Parameters        parameters.Parameters        `yaml:"parameters"`
 	StakingParameters parameters.StakingParameters `yaml:"staking_parameters"`
 	Transactions      []sdkTxs.Transaction
-	SuccessfulTxCount int64 `yaml:"-"`
+	SuccessfulTxCount int64             `yaml:"-"`
+	Snapshot          *snapshots.Report `yaml:"-"`
 	Function          interface{}
 }
 
@@ -58,6 +62,9 @@ func (testCase *TestCase) Initialize() {
 	if config.Configuration.Network.Timeout > 0 {
 		testCase.Parameters.Timeout = config.Configuration.Network.Timeout
 		testCase.StakingParameters.Timeout = config.Configuration.Network.Timeout
+	} else {
+		testCase.Parameters.Timeout = config.Configuration.Network.AdjustTimeout(testCase.Parameters.Timeout)
+		testCase.StakingParameters.Timeout = config.Configuration.Network.AdjustTimeout(testCase.StakingParameters.Timeout)
 	}
 }
 
@@ -84,6 +91,18 @@ func (testCase *TestCase) ReportMemoryDismissal() {
 	Title(testCase, "footer", testCase.Verbose)
 }
 
+// ReportBudgetDismissal - reports that a given test case was dismissed since the funding budget has been exhausted
+func (testCase *TestCase) ReportBudgetDismissal() {
+	msg := fmt.Sprintf(
+		"Skipping test case %s since the funding budget of %f has been exhausted - the current net funding cost is %f",
+		testCase.Name,
+		config.Configuration.Funding.Budget,
+		funding.Ledger.NetCost(),
+	)
+	testCase.Dismissal = fmt.Sprintf("Funding budget of %f exhausted", config.Configuration.Funding.Budget)
+	logger.WarningLog(msg, true)
+}
+
 // Successful - if the test case result matches the expected result
 func (testCase *TestCase) Successful() bool {
 	return testCase.Result == testCase.Expected
@@ -157,7 +176,7 @@ func (testCase *TestCase) HandleError(err error, account *sdkAccounts.Account, m
 
 		if account != nil {
 			logger.TeardownLog("Performing test teardown (returning funds and removing accounts)", testCase.Verbose)
-			Teardown(account, testCase.StakingParameters.FromShardID, config.Configuration.Funding.Account.Address, testCase.StakingParameters.FromShardID)
+			Teardown(account, config.Configuration.Funding.Account.Address)
 		}
 
 		logger.ResultLog(testCase.Result, testCase.Expected, testCase.Verbose)
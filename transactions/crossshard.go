@@ -0,0 +1,28 @@
+package transactions
+
+import (
+	"fmt"
+
+	sdkTxs "github.com/harmony-one/go-lib/transactions"
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/waits"
+)
+
+// WaitForCrossShardTransaction - waits until a successful cross shard transaction has been included in its source shard followed by network.waits.cross_shard_blocks blocks in the destination shard, i.e. until the receiver's balance reflects the transfer
+func WaitForCrossShardTransaction(tx sdkTxs.Transaction, verbose bool) {
+	if !tx.Success || tx.FromShardID == tx.ToShardID {
+		return
+	}
+
+	blocks := config.Configuration.Network.Waits.CrossShardBlocks
+	logger.TransactionLog(fmt.Sprintf("Waiting for cross shard transaction %s to be included in shard %d and for %d block(s) in shard %d to receive it", tx.TransactionHash, tx.FromShardID, blocks, tx.ToShardID), verbose)
+	if _, err := waits.Confirmations(tx.FromShardID, tx.TransactionHash, 0, 0); err != nil {
+		logger.WarningLog(fmt.Sprintf("Failed to wait for the inclusion of cross shard transaction %s - error: %s", tx.TransactionHash, err.Error()), verbose)
+		return
+	}
+
+	if _, err := waits.Blocks(tx.ToShardID, blocks, 0); err != nil {
+		logger.WarningLog(fmt.Sprintf("Failed to wait for cross shard transaction %s to be received in shard %d - error: %s", tx.TransactionHash, tx.ToShardID, err.Error()), verbose)
+	}
+}
@@ -0,0 +1,82 @@
+package transactions
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+
+	sdkTxs "github.com/harmony-one/go-lib/transactions"
+	"github.com/harmony-one/harmony/numeric"
+)
+
+// Gas - the gas spent by all transactions and staking transactions sent during the current run
+var Gas = &GasMeter{total: numeric.NewDec(0)}
+
+// GasMeter - sums up the gas cost of sent transactions
+type GasMeter struct {
+	mutex     sync.Mutex
+	total     numeric.Dec
+	estimated int
+}
+
+// Record - records the gas cost of a sent transaction - the maximum cost is recorded if its receipt isn't available
+func (meter *GasMeter) Record(rawTx map[string]interface{}, gasLimit int64, gasPrice numeric.Dec) {
+	cost, actual := GasCost(rawTx, gasLimit, gasPrice)
+
+	meter.mutex.Lock()
+	defer meter.mutex.Unlock()
+
+	meter.total = meter.total.Add(cost)
+	if !actual {
+		meter.estimated++
+	}
+}
+
+// Total - the total gas cost and the number of transactions whose cost had to be estimated
+func (meter *GasMeter) Total() (numeric.Dec, int) {
+	meter.mutex.Lock()
+	defer meter.mutex.Unlock()
+
+	return meter.total, meter.estimated
+}
+
+// GasCost - calculates the gas cost of a transaction using the gas used reported by its receipt
+// If the receipt isn't available (e.g. when not waiting for the transaction to finalize) the gas limit is used to calculate the maximum cost instead
+func GasCost(rawTx map[string]interface{}, gasLimit int64, gasPrice numeric.Dec) (cost numeric.Dec, actual bool) {
+	gasUsed, actual := gasUsed(rawTx)
+
+	if !actual {
+		calculatedGasLimit, err := sdkTxs.CalculateGasLimit(gasLimit, "", false)
+		if err != nil {
+			calculatedGasLimit = sdkTxs.TxGas
+		}
+		gasUsed = calculatedGasLimit
+	}
+
+	if gasPrice.IsNil() {
+		return numeric.NewDec(0), false
+	}
+
+	// Gas prices are specified in nano denominations
+	cost = numeric.NewDec(int64(gasUsed)).Mul(gasPrice).Quo(numeric.NewDec(1000000000))
+
+	return cost, actual
+}
+
+func gasUsed(rawTx map[string]interface{}) (uint64, bool) {
+	if rawTx == nil {
+		return 0, false
+	}
+
+	rawGasUsed, ok := rawTx["gasUsed"].(string)
+	if !ok || rawGasUsed == "" {
+		return 0, false
+	}
+
+	gasUsed, err := strconv.ParseUint(strings.TrimPrefix(rawGasUsed, "0x"), 16, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return gasUsed, true
+}
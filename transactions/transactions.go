@@ -8,29 +8,48 @@ import (
 	sdkTxs "github.com/harmony-one/go-lib/transactions"
 	"github.com/harmony-one/go-sdk/pkg/rpc"
 	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/confirmations"
+	"github.com/harmony-one/harmony-tf/network"
+	"github.com/harmony-one/harmony-tf/snapshots"
 	"github.com/harmony-one/harmony/numeric"
 )
 
 // SendTransaction - send transactions
+// Concurrent senders are limited by network.Senders which backs off when endpoints throttle requests - the limit only applies to sending, the receipt is awaited without holding a slot
 func SendTransaction(account *sdkAccounts.Account, fromShardID uint32, toAddress string, toShardID uint32, amount numeric.Dec, nonce int, gasLimit int64, gasPrice numeric.Dec, txData string, timeout int) (map[string]interface{}, error) {
-	account.Unlock()
+	snapshots.Transfer(account.Address, account.Name, fromShardID, toAddress, toShardID, amount, gasLimit, gasPrice)
+
+	network.Senders.Acquire()
+	txResult, err := sendTransaction(account, fromShardID, toAddress, toShardID, amount, nonce, gasLimit, gasPrice, txData)
+	network.Senders.Release()
 
-	rpcClient, currentNonce, err := TransactionPrerequisites(account, fromShardID, nonce)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(txData) > 0 {
-		txData = base64.StdEncoding.EncodeToString([]byte(txData))
+	if txResult, err = confirmations.Result(fromShardID, confirmations.Transaction, txResult, timeout); err != nil {
+		return nil, err
 	}
 
-	txResult, err := sdkTxs.SendTransaction(account.Keystore, account.Account, rpcClient, config.Configuration.Network.API.ChainID, account.Address, fromShardID, toAddress, toShardID, amount, gasLimit, gasPrice, currentNonce, txData, config.Configuration.Account.Passphrase, config.Configuration.Network.API.NodeAddress(fromShardID), timeout)
+	Gas.Record(txResult, gasLimit, gasPrice)
+
+	return txResult, nil
+}
 
+// sendTransaction - signs and sends a transaction without waiting for its receipt
+func sendTransaction(account *sdkAccounts.Account, fromShardID uint32, toAddress string, toShardID uint32, amount numeric.Dec, nonce int, gasLimit int64, gasPrice numeric.Dec, txData string) (map[string]interface{}, error) {
+	account.Unlock()
+
+	rpcClient, currentNonce, err := TransactionPrerequisites(account, fromShardID, nonce)
 	if err != nil {
 		return nil, err
 	}
 
-	return txResult, nil
+	if len(txData) > 0 {
+		txData = base64.StdEncoding.EncodeToString([]byte(txData))
+	}
+
+	return sdkTxs.SendTransaction(account.Keystore, account.Account, rpcClient, config.Configuration.Network.API.ChainID, account.Address, fromShardID, toAddress, toShardID, amount, gasLimit, gasPrice, currentNonce, txData, config.Configuration.Account.Passphrase, config.Configuration.Network.API.NodeAddress(fromShardID), 0)
 }
 
 // SendSameShardTransaction - send a transaction using the same shard for both the receiver and the sender
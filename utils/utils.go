@@ -18,13 +18,10 @@ func PrefixAddress(prefix string, address string) string {
 	return fmt.Sprintf("%s_%s", strings.Title(prefix), address)
 }
 
-// NetworkTimeoutAdjustment - adjusts the wait time based on different networks
-func NetworkTimeoutAdjustment(networkName string, currentTimeout int) int {
-	if currentTimeout > 0 {
-		switch networkName {
-		case "localnet", "staking", "stress":
-			currentTimeout = int(math.RoundToEven(float64(currentTimeout) * 1.5))
-		}
+// NetworkTimeoutAdjustment - adjusts the wait time using the timeout multiplier of a network
+func NetworkTimeoutAdjustment(multiplier float64, currentTimeout int) int {
+	if currentTimeout > 0 && multiplier > 0 {
+		currentTimeout = int(math.RoundToEven(float64(currentTimeout) * multiplier))
 	}
 
 	return currentTimeout
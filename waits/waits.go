@@ -0,0 +1,172 @@
+package waits
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/harmony-one/harmony-tf/config"
+	"github.com/harmony-one/harmony-tf/logger"
+	"github.com/harmony-one/harmony-tf/metrics"
+	"github.com/harmony-one/harmony-tf/network"
+)
+
+// UntilBlock - waits until a given shard has reached a given block number - a timeout of 0 uses network.waits.timeout
+func UntilBlock(shardID uint32, blockNumber uint64, timeout time.Duration) (current uint64, err error) {
+	node, err := shardNode(shardID)
+	if err != nil {
+		return 0, err
+	}
+
+	description := fmt.Sprintf("block %d in shard %d", blockNumber, shardID)
+	err = poll(description, timeout, func() (bool, string, error) {
+		current, err = network.BlockNumber(node)
+		if err != nil {
+			return false, "", err
+		}
+		return current >= blockNumber, fmt.Sprintf("current block: %d", current), nil
+	})
+
+	return current, err
+}
+
+// Blocks - waits until a given number of new blocks have been produced in a given shard
+func Blocks(shardID uint32, count uint64, timeout time.Duration) (uint64, error) {
+	node, err := shardNode(shardID)
+	if err != nil {
+		return 0, err
+	}
+
+	start, err := network.BlockNumber(node)
+	if err != nil {
+		return 0, err
+	}
+
+	return UntilBlock(shardID, start+count, timeout)
+}
+
+// UntilEpoch - waits until a given shard has reached a given epoch
+func UntilEpoch(shardID uint32, epoch uint64, timeout time.Duration) (current uint64, err error) {
+	node, err := shardNode(shardID)
+	if err != nil {
+		return 0, err
+	}
+
+	description := fmt.Sprintf("epoch %d in shard %d", epoch, shardID)
+	err = poll(description, timeout, func() (bool, string, error) {
+		current, err = network.Epoch(node)
+		if err != nil {
+			return false, "", err
+		}
+		return current >= epoch, fmt.Sprintf("current epoch: %d", current), nil
+	})
+
+	return current, err
+}
+
+// NextEpoch - waits until a given shard has moved on to the next epoch
+func NextEpoch(shardID uint32, timeout time.Duration) (uint64, error) {
+	node, err := shardNode(shardID)
+	if err != nil {
+		return 0, err
+	}
+
+	current, err := network.Epoch(node)
+	if err != nil {
+		return 0, err
+	}
+
+	return UntilEpoch(shardID, current+1, timeout)
+}
+
+// Confirmations - waits until a given (staking) transaction has been included in a block of a given shard followed by a given number of confirmation blocks
+// Returns the number of the block the transaction was included in
+func Confirmations(shardID uint32, txHash string, confirmations uint64, timeout time.Duration) (blockNumber uint64, err error) {
+	node, err := shardNode(shardID)
+	if err != nil {
+		return 0, err
+	}
+
+	timeout = resolveTimeout(timeout)
+	deadline := time.Now().Add(timeout)
+
+	description := fmt.Sprintf("transaction %s to be included in shard %d", txHash, shardID)
+	err = poll(description, timeout, func() (bool, string, error) {
+		var found bool
+		blockNumber, found, err = network.TransactionBlockNumber(node, txHash)
+		if err != nil {
+			return false, "", err
+		}
+		if !found {
+			return false, "pending", nil
+		}
+		return true, fmt.Sprintf("included in block %d", blockNumber), nil
+	})
+	if err != nil || confirmations == 0 {
+		return blockNumber, err
+	}
+
+	// UntilBlock treats a timeout of 0 as the default timeout - an exhausted deadline must fail instead of starting a new wait
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		return blockNumber, fmt.Errorf("timed out after %v waiting for %d confirmation(s) of transaction %s included in block %d", timeout, confirmations, txHash, blockNumber)
+	}
+
+	if _, err = UntilBlock(shardID, blockNumber+confirmations, remaining); err != nil {
+		return blockNumber, fmt.Errorf("transaction %s was included in block %d but didn't reach %d confirmation(s): %s", txHash, blockNumber, confirmations, err.Error())
+	}
+
+	return blockNumber, nil
+}
+
+// poll - calls check every network.waits.poll_interval until it reports that the wait is over or the deadline has passed
+// Errors returned by check are treated as transient and only returned when the deadline has passed
+func poll(description string, timeout time.Duration, check func() (done bool, progress string, err error)) error {
+	timeout = resolveTimeout(timeout)
+	deadline := time.Now().Add(timeout)
+	startedAt := time.Now()
+	lastProgress := ""
+
+	for {
+		done, progress, err := check()
+		if err == nil && done {
+			logger.Log(fmt.Sprintf("Reached %s after %v - %s", description, time.Since(startedAt).Round(time.Millisecond), progress), config.Configuration.Framework.Verbose)
+			return nil
+		}
+
+		if err == nil && progress != lastProgress {
+			logger.Log(fmt.Sprintf("Waiting for %s - %s", description, progress), config.Configuration.Framework.Verbose)
+			lastProgress = progress
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			if err != nil {
+				return fmt.Errorf("timed out after %v waiting for %s - last error: %s", timeout, description, err.Error())
+			}
+			return fmt.Errorf("timed out after %v waiting for %s - %s", timeout, description, lastProgress)
+		}
+
+		interval := time.Duration(config.Configuration.Network.Waits.PollInterval) * time.Second
+		if interval > remaining {
+			interval = remaining
+		}
+		metrics.Sleep("chain progress poll", interval)
+	}
+}
+
+func resolveTimeout(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return time.Duration(config.Configuration.Network.Waits.Timeout) * time.Second
+	}
+
+	return timeout
+}
+
+func shardNode(shardID uint32) (string, error) {
+	shard, ok := config.Configuration.Network.API.Shards[shardID]
+	if !ok || shard.Node == "" {
+		return "", fmt.Errorf("shard %d doesn't exist on the %s network", shardID, config.Configuration.Network.Name)
+	}
+
+	return shard.Node, nil
+}